@@ -0,0 +1,240 @@
+package mpb
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/vbauerster/mpb/internal"
+)
+
+// aggrRow is a snapshot of a bar, which takes part in aggregation.
+type aggrRow struct {
+	total   int64
+	current int64
+	elapsed time.Duration
+	spinner bool
+	done    bool
+	failed  bool
+	aborted bool
+}
+
+// aggrCounts counts done bars, which take part in aggregation.
+type aggrCounts struct {
+	succeeded, failed, aborted int
+	// failedTotal and failedCurrent are sums of failed bars' values
+	failedTotal, failedCurrent int64
+}
+
+// AddAggregateBar creates a bar, which reflects overall progress of all other
+// bars of the container, including ones added later. Its total and current
+// are sums of respective values of other bars, updated each render cycle.
+// Aggregate bar is completed, once all other bars are done, and it is
+// never removed by the container, so there is no need to wait for it.
+// Succeeded, failed and aborted bars are counted, see Bar.Fail, and failed
+// share of work is rendered as a separate segment, red if color is enabled.
+// Bars of unknown total are left out of sums, until their total is set,
+// though aggregate bar isn't completed, while they're running.
+func (p *Progress) AddAggregateBar(options ...BarOption) *Bar {
+	result := make(chan *Bar)
+	select {
+	case p.operateState <- func(s *pState) {
+		wg := new(sync.WaitGroup)
+		wg.Add(1)
+		// container defaults go first, so bar's own options take precedence
+		options = append([]BarOption{barWidth(s.width), barStyle(s.style), barColor(s.color), BarClock(s.clock)}, options...)
+		options = append(options, barAggregate())
+		b := newBar(wg, s.idCounter, 0, nil, options...)
+		if len(s.aggregates) == 0 {
+			// start tracking bars, which were added so far
+			for _, bar := range *s.bHeap {
+				s.children = append(s.children, bar)
+			}
+			for _, bars := range s.waitBars {
+				s.children = append(s.children, bars...)
+			}
+		}
+		heap.Push(s.bHeap, b)
+		s.heapUpdated = true
+		s.aggregates = append(s.aggregates, b)
+		b.container = p
+		s.idCounter++
+		result <- b
+	}:
+		return <-result
+	case <-p.done:
+		return nil
+	}
+}
+
+func (s *pState) updateAggregates() {
+	rows := make([]aggrRow, 0, len(s.children))
+	alive := s.children[:0]
+	for _, b := range s.children {
+		row := b.aggrRow()
+		if row.spinner {
+			// total is a placeholder, only work done is known
+			row.total = row.current
+		}
+		if row.done {
+			// done bars have nothing to estimate anymore,
+			// so fold them into the base and forget
+			s.aggrBase.total += row.total
+			s.aggrBase.current += row.current
+			switch {
+			case row.aborted:
+				s.aggrCounts.aborted++
+			case row.failed:
+				s.aggrCounts.failed++
+				s.aggrCounts.failedTotal += row.total
+				s.aggrCounts.failedCurrent += row.current
+			default:
+				s.aggrCounts.succeeded++
+			}
+			continue
+		}
+		alive = append(alive, b)
+		rows = append(rows, row)
+	}
+	s.children = alive
+	for _, b := range s.aggregates {
+		b.aggregate(s.aggrBase, s.aggrCounts, rows)
+	}
+}
+
+// shutdownAggregates lets aggregates count all other bars, which are done
+// by now, drawing one more frame, if some of them have been done since the
+// last one, so aggregates' last frame isn't stale.
+func (s *pState) shutdownAggregates() {
+	if len(s.aggregates) > 0 && len(s.children) > 0 && !s.held {
+		tw, err := s.cw.GetWidth()
+		if err != nil {
+			tw = s.width
+		}
+		s.render(tw)
+	}
+	if len(s.aggregates) > 0 {
+		s.updateAggregates()
+	}
+	for _, b := range s.aggregates {
+		close(b.shutdown)
+	}
+}
+
+func (b *Bar) aggrRow() aggrRow {
+	result := make(chan aggrRow, 1)
+	select {
+	case b.operateState <- func(s *bState) {
+		result <- aggrRow{
+			total:   s.total,
+			current: s.current,
+			elapsed: s.elapsed(),
+			spinner: s.spinner,
+		}
+	}:
+		return <-result
+	case <-b.done:
+		row := aggrRow{
+			total:   b.cacheState.total,
+			current: b.cacheState.current,
+			spinner: b.cacheState.spinner,
+			done:    true,
+			failed:  b.cacheState.failed,
+			aborted: b.cacheState.aborted,
+		}
+		select {
+		case <-b.aborted:
+			row.aborted = true
+		default:
+		}
+		return row
+	}
+}
+
+func (b *Bar) aggregate(base aggrRow, counts aggrCounts, rows []aggrRow) {
+	select {
+	case b.operateState <- func(s *bState) {
+		s.total, s.current = base.total, base.current
+		s.aggrCounts = counts
+		for _, row := range rows {
+			if row.spinner {
+				continue
+			}
+			s.total += row.total
+			s.current += row.current
+		}
+		s.toComplete = len(rows) == 0 && s.total > 0
+		if s.criticalPath {
+			s.eta = criticalPathETA(rows)
+		} else {
+			s.eta = averageETA(s.elapsed(), s.total, s.current)
+		}
+	}:
+	case <-b.done:
+	}
+}
+
+// criticalPathETA estimates remaining time by the slowest bar,
+// as aggregate work is not done until the slowest one is done.
+func criticalPathETA(rows []aggrRow) (eta time.Duration) {
+	for _, row := range rows {
+		if row.spinner {
+			continue
+		}
+		if e := averageETA(row.elapsed, row.total, row.current); e > eta {
+			eta = e
+		}
+	}
+	return eta
+}
+
+func averageETA(elapsed time.Duration, total, current int64) time.Duration {
+	if current <= 0 || current >= total {
+		return 0
+	}
+	perItem := float64(elapsed) / float64(current)
+	return time.Duration(perItem * float64(total-current))
+}
+
+// fillFailed draws failed share of aggregate bar first, then succeeded one.
+func (s *bState) fillFailed(barWidth int64) {
+	resolved := s.current - s.aggrCounts.failedCurrent + s.aggrCounts.failedTotal
+	completedWidth := internal.Percentage(s.total, resolved, barWidth)
+	failedWidth := internal.Percentage(s.total, s.aggrCounts.failedTotal, barWidth)
+	if failedWidth > completedWidth {
+		failedWidth = completedWidth
+	}
+
+	if s.color {
+		s.bufB.WriteString(sgrRed)
+	}
+	for i := int64(0); i < failedWidth; i++ {
+		if s.color {
+			s.bufB.WriteString(s.style.Fill)
+		} else {
+			s.bufB.WriteRune(failedRune)
+		}
+	}
+	if s.color {
+		s.bufB.WriteString(sgrReset)
+	}
+
+	var tip string
+	tipWidth := int64(internal.DisplayWidth(s.style.Tip))
+	if completedWidth < barWidth && completedWidth-failedWidth >= tipWidth {
+		tip = s.style.Tip
+	} else {
+		tipWidth = 0
+	}
+	s.fillCells(completedWidth-failedWidth-tipWidth, "", s.style.Fill, tip, "")
+	s.fillCells(barWidth-completedWidth, "", s.style.Empty, "", "")
+}
+
+func (s *pState) isAggregate(b *Bar) bool {
+	for _, aggr := range s.aggregates {
+		if aggr == b {
+			return true
+		}
+	}
+	return false
+}
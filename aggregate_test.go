@@ -0,0 +1,135 @@
+package mpb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCriticalPathETA(t *testing.T) {
+	rows := []aggrRow{
+		// 1s per item, 10 remaining
+		{total: 100, current: 90, elapsed: 90 * time.Second},
+		// 1s per item, 50 remaining
+		{total: 100, current: 50, elapsed: 50 * time.Second},
+		// nothing done, unknown
+		{total: 100, current: 0, elapsed: 50 * time.Second},
+	}
+
+	want := 50 * time.Second
+	if got := criticalPathETA(rows); got != want {
+		t.Errorf("want: %v, got: %v\n", want, got)
+	}
+}
+
+func TestAggregateBar(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard), WithRefreshRate(10*time.Millisecond))
+	aggr := p.AddAggregateBar()
+
+	totals := []int64{10, 20, 30}
+	for _, total := range totals {
+		b := p.AddBar(total)
+		go func(total int64) {
+			for i := int64(0); i < total; i++ {
+				time.Sleep(time.Millisecond)
+				b.Increment()
+			}
+		}(total)
+	}
+
+	p.Wait()
+
+	if got := aggr.Current(); got != 60 {
+		t.Errorf("aggregate current want: %d, got: %d\n", 60, got)
+	}
+}
+
+func TestAggregateBarUnknownTotal(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard), WithRefreshRate(10*time.Millisecond))
+	aggr := p.AddAggregateBar()
+
+	known := p.AddBar(10)
+	unknown := p.AddBar(0)
+	known.IncrBy(5)
+	unknown.IncrBy(5)
+	time.Sleep(50 * time.Millisecond)
+
+	if got := aggr.Current(); got != 5 {
+		t.Errorf("aggregate current want: %d, got: %d\n", 5, got)
+	}
+
+	known.IncrBy(5)
+	unknown.Complete()
+	p.Wait()
+
+	if got := aggr.Current(); got != 15 {
+		t.Errorf("aggregate current want: %d, got: %d\n", 15, got)
+	}
+}
+
+func TestAggregateBarFailures(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard), WithRefreshRate(10*time.Millisecond))
+	aggr := p.AddAggregateBar()
+
+	succeeded := p.AddBar(10)
+	failed := p.AddBar(10)
+	aborted := p.AddBar(10)
+
+	succeeded.IncrBy(10)
+	failed.IncrBy(5)
+	failed.Fail()
+	p.Abort(aborted, false)
+
+	p.Wait()
+
+	st := aggr.statistics()
+	if st.Succeeded != 1 || st.Failed != 1 || st.Aborted != 1 {
+		t.Errorf("want 1/1/1 succeeded/failed/aborted, got %d/%d/%d\n", st.Succeeded, st.Failed, st.Aborted)
+	}
+}
+
+func TestAggregateBarColor(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend), WithWidth(12),
+		WithColorMode(ColorAlways), WithManualRefresh(make(chan time.Time)))
+	// user's style isn't overridden by container's one
+	p.AddAggregateBar(BarTrim(), BarWithStyle(StyleFromFormat("(#>.)")))
+	failed := p.AddBar(100, BarTrim())
+	running := p.AddBar(100, BarTrim())
+
+	failed.IncrBy(50)
+	failed.Fail()
+	// final frame of failed bar, then its shutdown, then the fold
+	for i := 0; i < 3; i++ {
+		p.Refresh()
+	}
+	if !strings.Contains(buf.String(), "(\x1b[31m") {
+		t.Errorf("failed part of aggregate isn't colored: %q", buf.String())
+	}
+
+	running.IncrBy(100)
+	p.Wait()
+}
+
+func TestAggregateBarLastFrame(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend), WithWidth(12))
+	aggr := p.AddAggregateBar(BarTrim())
+	a := p.AddBar(100, BarTrim())
+	b := p.AddBar(100, BarTrim())
+
+	a.IncrBy(50)
+	a.Fail()
+	b.IncrBy(100)
+	p.Wait()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got, want := lines[len(lines)-3], "[xxxxx=====]"; got != want {
+		t.Errorf("want aggregate's last frame %q, got %q", want, got)
+	}
+	if st := aggr.statistics(); st.Succeeded != 1 || st.Failed != 1 {
+		t.Errorf("want 1/1 succeeded/failed, got %d/%d", st.Succeeded, st.Failed)
+	}
+}
@@ -2,35 +2,59 @@ package mpb
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-	"unicode/utf8"
 
 	"github.com/vbauerster/mpb/decor"
 	"github.com/vbauerster/mpb/internal"
 )
 
-const (
-	rLeft = iota
-	rFill
-	rTip
-	rEmpty
-	rRight
-)
+// finishingFrames are rendered in place of the last fill rune,
+// while the bar is in finishing phase.
+var finishingFrames = []rune{'-', '\\', '|', '/'}
 
-const formatLen = 5
+var (
+	// ErrAborted is abort reason of bars, aborted by Progress.Abort.
+	ErrAborted = errors.New("mpb: bar aborted")
+	// ErrCancelled is abort reason of bars, cancelled by container's cancel
+	// chan, see WithCancel. WithContext makes context's error the reason.
+	ErrCancelled = errors.New("mpb: container cancelled")
+	// ErrShutdownTimeout is abort reason of bars, which are still running,
+	// once shutdown timeout expires, see WithShutdownTimeout.
+	ErrShutdownTimeout = errors.New("mpb: shutdown timeout")
+)
 
-type barRunes [formatLen]rune
+const (
+	// max number of ETA samples, kept for ETA accuracy report
+	maxETASamples = 1024
+	sgrInverse    = "\x1b[7m"
+	sgrReset      = "\x1b[0m"
+	sgrRed        = "\x1b[31m"
+	// failedRune is drawn for failed share of aggregate bar, if no color
+	failedRune = 'x'
+)
 
 // Bar represents a progress Bar
 type Bar struct {
 	priority int
 	index    int
 
-	runningBar    *Bar
+	runningBar *Bar
+	takeOver   bool
+	// hidden is set by master Progress goroutine, while the bar is out of
+	// the heap by Hide or BarHidden
+	hidden bool
+	// finished is set by master Progress goroutine, once the bar's final
+	// frame has been flushed or it has been aborted
+	finished      bool
+	container     *Progress
+	ioCounter     *int64
+	clock         Clock
 	cacheState    *bState
 	operateState  chan func(*bState)
 	int64Ch       chan int64
@@ -39,10 +63,18 @@ type Bar struct {
 	syncTableCh   chan [][]chan int
 	bufNL         *bytes.Buffer
 
+	// frame is reused by the bar's goroutine, as previous one is always
+	// flushed by the time next one is rendered
+	frame frameReader
+
 	// done is closed by Bar's goroutine, after cacheState is written
 	done chan struct{}
 	// shutdown is closed from master Progress goroutine only
 	shutdown chan struct{}
+	// aborted is closed from master Progress goroutine only, on Abort
+	aborted chan struct{}
+	// cancel is container's cancel chan, if any
+	cancel <-chan struct{}
 }
 
 type (
@@ -51,39 +83,136 @@ type (
 		width              int
 		total              int64
 		current            int64
-		runes              barRunes
+		style              BarStyle
+		reverse            bool
 		trimLeftSpace      bool
 		trimRightSpace     bool
 		toComplete         bool
 		removeOnComplete   bool
 		barClearOnComplete bool
+		completeMsgFn      decor.DecorFunc
+		onFinalFrame       func()
 		completeFlushed    bool
 		aDecorators        []decor.Decorator
 		pDecorators        []decor.Decorator
 		amountReceivers    []decor.AmountReceiver
 		shutdownListeners  []decor.ShutdownListener
+		pauseListeners     []decor.PauseListener
+		pausedAt           time.Time
+		abortReason        error
+		cancelReason       func() error
+		etaEstimator       EtaEstimator
+		lastIncr           time.Time
+		clock              Clock
+		detached           bool
 		refill             *refill
 		bufP, bufB, bufA   *bytes.Buffer
+		sections           sections
+		stat               decor.Statistics
 		panicMsg           string
 		newLineExtendFn    func(io.Writer, bool)
+		extender           decor.DecorFunc
+		subStatus          string
+		startTime          time.Time
+		attempts           int
+		finishing          bool
+		finishingFrame     int
+		wrapMinWidth       int
+		flashFrames        int
+		flashCount         int
+		color              bool
+		shards             []*Shard
+		phase              string
+		phaseStart         time.Time
+		phaseDurations     map[string]time.Duration
+		fillColor          string
+		emptyColor         string
+		abortColor         string
+		abortMark          string
+		chunks             []chunk
+		reuseDecor         bool
+		pCache, aCache     []string
+		etaSamples         []time.Time
+		speedSamples       []speedSample
+		speedWindow        time.Duration
+		etaStep            int
+		etaFrame           int
+		etaError           time.Duration
+		etaErrorDone       bool
+		failed             bool
+		aborted            bool
+		aggrCounts         aggrCounts
+		spinner            bool
+		spinnerPos         SpinnerPosition
+		spinnerCount       int
+		spinnerFrames      []string
+		spinnerWidth       int
+		spinnerInterval    time.Duration
+		spinnerLast        time.Time
+		decorated          int
+		readOps            int64
+		readBytes          int64
+		sourceReadOps      int64
+		locale             *decor.Locale
+		writeOps           int64
+		writeBytes         int64
+		aggregate          bool
+		criticalPath       bool
+		eta                time.Duration
 
 		// following options are assigned to the *Bar
 		priority   int
 		runningBar *Bar
+		takeOver   bool
+		hidden     bool
+		ioCounter  *int64
 	}
 	refill struct {
-		char rune
-		till int64
+		char  rune
+		till  int64
+		color string
 	}
 	frameReader struct {
 		io.Reader
+		// err is why the bar has ended abnormally, reported by Progress.Close
+		err              error
 		toShutdown       bool
 		removeOnComplete bool
+		onFinalFrame     func()
+	}
+
+	// sections reads buffers one after another, like io.MultiReader does,
+	// but is reused across frames, instead of being allocated each one.
+	sections struct {
+		bufs [3]*bytes.Buffer
+		n    int
 	}
 )
 
+func (r *sections) reset(bufs ...*bytes.Buffer) io.Reader {
+	r.n = copy(r.bufs[:], bufs)
+	return r
+}
+
+func (r *sections) Read(p []byte) (n int, err error) {
+	for n < len(p) && r.n > 0 {
+		m, _ := r.bufs[0].Read(p[n:])
+		n += m
+		if r.bufs[0].Len() == 0 {
+			copy(r.bufs[:], r.bufs[1:r.n])
+			r.n--
+		}
+	}
+	if n == 0 && r.n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
+
 func newBar(wg *sync.WaitGroup, id int, total int64, cancel <-chan struct{}, options ...BarOption) *Bar {
-	if total <= 0 {
+	// total is unknown, so render spinner until it is set
+	spinner := total <= 0
+	if spinner {
 		total = time.Now().Unix()
 	}
 
@@ -91,6 +220,8 @@ func newBar(wg *sync.WaitGroup, id int, total int64, cancel <-chan struct{}, opt
 		id:       id,
 		priority: id,
 		total:    total,
+		spinner:  spinner,
+		attempts: 1,
 	}
 
 	for _, opt := range options {
@@ -99,13 +230,32 @@ func newBar(wg *sync.WaitGroup, id int, total int64, cancel <-chan struct{}, opt
 		}
 	}
 
+	s.startTime = s.now()
+	if s.clock != nil {
+		for _, d := range s.pDecorators {
+			if r, ok := d.(decor.ClockReceiver); ok {
+				r.SetClock(s.clock)
+			}
+		}
+		for _, d := range s.aDecorators {
+			if r, ok := d.(decor.ClockReceiver); ok {
+				r.SetClock(s.clock)
+			}
+		}
+	}
+
 	s.bufP = bytes.NewBuffer(make([]byte, 0, s.width))
 	s.bufB = bytes.NewBuffer(make([]byte, 0, s.width))
 	s.bufA = bytes.NewBuffer(make([]byte, 0, s.width))
 
 	b := &Bar{
 		priority:      s.priority,
+		index:         -1,
 		runningBar:    s.runningBar,
+		takeOver:      s.takeOver,
+		hidden:        s.hidden,
+		ioCounter:     s.ioCounter,
+		clock:         s.clock,
 		operateState:  make(chan func(*bState)),
 		int64Ch:       make(chan int64),
 		boolCh:        make(chan bool),
@@ -113,16 +263,14 @@ func newBar(wg *sync.WaitGroup, id int, total int64, cancel <-chan struct{}, opt
 		syncTableCh:   make(chan [][]chan int),
 		done:          make(chan struct{}),
 		shutdown:      make(chan struct{}),
+		aborted:       make(chan struct{}),
+		cancel:        cancel,
 	}
 
 	if b.runningBar != nil {
 		b.priority = b.runningBar.priority
 	}
 
-	if s.newLineExtendFn != nil {
-		b.bufNL = bytes.NewBuffer(make([]byte, 0, s.width))
-	}
-
 	go b.serve(wg, s, cancel)
 	return b
 }
@@ -130,7 +278,7 @@ func newBar(wg *sync.WaitGroup, id int, total int64, cancel <-chan struct{}, opt
 // RemoveAllPrependers removes all prepend functions.
 func (b *Bar) RemoveAllPrependers() {
 	select {
-	case b.operateState <- func(s *bState) { s.pDecorators = nil }:
+	case b.operateState <- func(s *bState) { s.pDecorators = hideDecorators(s.pDecorators) }:
 	case <-b.done:
 	}
 }
@@ -138,11 +286,28 @@ func (b *Bar) RemoveAllPrependers() {
 // RemoveAllAppenders removes all append functions.
 func (b *Bar) RemoveAllAppenders() {
 	select {
-	case b.operateState <- func(s *bState) { s.aDecorators = nil }:
+	case b.operateState <- func(s *bState) { s.aDecorators = hideDecorators(s.aDecorators) }:
 	case <-b.done:
 	}
 }
 
+// hiddenDecorator renders nothing, but still takes part in width sync,
+// because the container's sync matrix keeps expecting it.
+type hiddenDecorator struct {
+	decor.Decorator
+}
+
+func (hiddenDecorator) Decor(*decor.Statistics) string { return "" }
+
+func hideDecorators(ds []decor.Decorator) (hidden []decor.Decorator) {
+	for _, d := range ds {
+		if ok, _ := d.Syncable(); ok {
+			hidden = append(hidden, hiddenDecorator{d})
+		}
+	}
+	return hidden
+}
+
 // ProxyReader allows progress tracking against provided io.Reader.
 func (b *Bar) ProxyReader(r io.Reader) *Reader {
 	proxyReader := &Reader{
@@ -152,6 +317,41 @@ func (b *Bar) ProxyReader(r io.Reader) *Reader {
 	return proxyReader
 }
 
+// ProxyReaderSize is ProxyReader, which reads from r into internal buffer
+// of provided size, until it's full or r returns an error, so progress is
+// reported by chunks of size bytes at most, no matter how small reads of r
+// are. This smooths ewma based speed estimates and cuts per read overhead.
+// As a trade-off, a read may block until the whole chunk has been read.
+// See decor.Statistics.SourceReadOps for number of reads of r.
+func (b *Bar) ProxyReaderSize(r io.Reader, size int) *Reader {
+	proxyReader := b.ProxyReader(r)
+	if size > 0 {
+		proxyReader.chunk = &chunkBuffer{buf: make([]byte, size)}
+	}
+	return proxyReader
+}
+
+// ProxyReadCloser is ProxyReader, which completes the bar on io.EOF or Close,
+// whichever comes first, regardless of bar's current progress.
+func (b *Bar) ProxyReadCloser(rc io.ReadCloser) io.ReadCloser {
+	return &readCloser{Reader: b.ProxyReader(rc)}
+}
+
+// ProxyWriter allows progress tracking against provided io.Writer.
+func (b *Bar) ProxyWriter(w io.Writer) *Writer {
+	proxyWriter := &Writer{
+		Writer: w,
+		bar:    b,
+	}
+	return proxyWriter
+}
+
+// ProxyWriteCloser is ProxyWriter, which completes the bar on Close,
+// regardless of bar's current progress.
+func (b *Bar) ProxyWriteCloser(wc io.WriteCloser) io.WriteCloser {
+	return &writeCloser{Writer: b.ProxyWriter(wc)}
+}
+
 // ID returs id of the bar.
 func (b *Bar) ID() int {
 	select {
@@ -172,27 +372,76 @@ func (b *Bar) Current() int64 {
 	}
 }
 
+// statistics returns snapshot of bar's statistics.
+func (b *Bar) statistics() *decor.Statistics {
+	result := make(chan *decor.Statistics, 1)
+	select {
+	case b.operateState <- func(s *bState) { result <- newStatistics(s) }:
+		return <-result
+	case <-b.done:
+		return newStatistics(b.cacheState)
+	}
+}
+
 // SetTotal sets total dynamically.
+// Bar, which was created with unknown total, is rendered as spinner,
+// until positive total is set. If total changes, ETA samples collected so
+// far are dropped, as they were estimated against the old total.
 // Set final to true, when total is known, it will trigger bar complete event.
 func (b *Bar) SetTotal(total int64, final bool) {
-	b.operateState <- func(s *bState) {
-		if total > 0 {
+	select {
+	case b.operateState <- func(s *bState) {
+		if total > 0 && (s.spinner || total != s.total) {
 			s.total = total
+			s.spinner = false
+			s.etaSamples = s.etaSamples[:0]
+			if s.current >= s.total {
+				s.current = s.total
+				s.toComplete = !s.finishing
+			}
 		}
 		if final {
 			s.current = s.total
-			s.toComplete = true
+			s.toComplete = !s.finishing
 		}
+	}:
+	case <-b.done:
+	}
+}
+
+// SetCurrent sets absolute progress position, clamped to [0, total].
+// Setting it to total or above triggers bar complete event. Moving it back
+// has no effect, if the bar is already completed.
+func (b *Bar) SetCurrent(n int64, wdd ...time.Duration) {
+	select {
+	case b.operateState <- func(s *bState) { s.incrBy(n-s.current, wdd...) }:
+	case <-b.done:
 	}
 }
 
 // SetRefill sets fill rune to r, up until n.
 func (b *Bar) SetRefill(n int, r rune) {
+	b.SetRefillInt64(int64(n), r)
+}
+
+// SetRefillInt64 is SetRefill with int64 amount, which doesn't overflow
+// on 32-bit platforms.
+func (b *Bar) SetRefillInt64(n int64, r rune) {
+	b.SetRefillStyle(n, r, "")
+}
+
+// SetRefillStyle is SetRefillInt64, which also colors refilled part of the
+// bar with provided SGR sequence, like decor.ColorBlue, if color is enabled.
+// Intended for resumed work, n is the resume point: increments up to it
+// are exposed via decor.Statistics.Refilled and aren't taken into account
+// by speed and ETA estimates, as they have been done before.
+func (b *Bar) SetRefillStyle(n int64, r rune, color string) {
 	if n <= 0 {
 		return
 	}
-	b.operateState <- func(s *bState) {
-		s.refill = &refill{r, int64(n)}
+	select {
+	case b.operateState <- func(s *bState) { s.refill = &refill{r, n, color} }:
+	case <-b.done:
 	}
 }
 
@@ -207,24 +456,221 @@ func (b *Bar) Increment() {
 }
 
 // IncrBy increments progress bar by amount of n.
+// Negative n rolls progress back, e.g. for a retried chunk, though never
+// below zero. Rolling back has no effect, if the bar is already completed.
 // wdd is optional work duration i.e. time.Since(start),
 // which expected to be provided, if any ewma based decorator is used.
+// It's safe to call from multiple goroutines, though each call is served
+// by the bar's goroutine, consider ShardedCounter for many producers.
 func (b *Bar) IncrBy(n int, wdd ...time.Duration) {
+	b.IncrInt64(int64(n), wdd...)
+}
+
+// IncrInt64 is IncrBy with int64 amount, which doesn't overflow on 32-bit
+// platforms, when tracking multi-GB amounts.
+func (b *Bar) IncrInt64(n int64, wdd ...time.Duration) {
+	select {
+	case b.operateState <- func(s *bState) { s.incrBy(n, wdd...) }:
+	case <-b.done:
+	}
+}
+
+// readBy is IncrBy, which also counts read op, used by proxy reader.
+// Read, which returned no data, like final one at io.EOF, isn't counted.
+// srcOps is number of source reads, the chunk of n bytes took.
+func (b *Bar) readBy(n int, srcOps int64, wd time.Duration) {
+	b.countIO(n)
 	select {
 	case b.operateState <- func(s *bState) {
-		s.current += int64(n)
-		if s.current >= s.total {
-			s.current = s.total
-			s.toComplete = true
+		if n > 0 {
+			s.readOps++
 		}
-		for _, ar := range s.amountReceivers {
-			ar.NextAmount(n, wdd...)
+		s.sourceReadOps += srcOps
+		s.readBytes += int64(n)
+		s.incrBy(int64(n), wd)
+	}:
+	case <-b.done:
+	}
+}
+
+// writeBy is IncrBy, which also counts write op, used by proxy writer.
+func (b *Bar) writeBy(n int, wd time.Duration) {
+	b.countIO(n)
+	select {
+	case b.operateState <- func(s *bState) {
+		s.writeOps++
+		s.writeBytes += int64(n)
+		s.incrBy(int64(n), wd)
+	}:
+	case <-b.done:
+	}
+}
+
+// countIO adds n to container's io counter, see Progress.IOBytes.
+func (b *Bar) countIO(n int) {
+	if b.ioCounter != nil && n > 0 {
+		atomic.AddInt64(b.ioCounter, int64(n))
+	}
+}
+
+// Reset resets bar's progress to zero and increments its attempts count,
+// which is exposed via Statistics.Attempts. Intended for retrying failed work.
+// Has no effect, if the bar is already completed.
+func (b *Bar) Reset() {
+	select {
+	case b.operateState <- func(s *bState) {
+		if s.toComplete {
+			return
 		}
+		s.current = 0
+		s.restartClock(s.now())
+		s.attempts++
+		s.etaSamples = s.etaSamples[:0]
+		s.speedSamples = s.speedSamples[:0]
 	}:
 	case <-b.done:
 	}
 }
 
+// SetFinishing marks the bar as having finalization phase (fsync, checksum,
+// commit and so on). Once such bar reaches its total, it is not completed,
+// but rendered with a spinner in place of the last fill rune, until Complete
+// is called. Has no effect, if the bar is already completed.
+func (b *Bar) SetFinishing() {
+	select {
+	case b.operateState <- func(s *bState) {
+		if !s.toComplete {
+			s.finishing = true
+		}
+	}:
+	case <-b.done:
+	}
+}
+
+// Complete completes the bar, regardless of its current progress.
+// Intended to end finishing phase, see SetFinishing. If total is unknown,
+// it is set to current.
+func (b *Bar) Complete() {
+	select {
+	case b.operateState <- func(s *bState) {
+		if s.spinner {
+			// total was unknown, so it is what has been done
+			s.spinner = false
+			s.total = s.current
+		}
+		s.current = s.total
+		s.finishing = false
+		s.toComplete = true
+	}:
+	case <-b.done:
+	}
+}
+
+// SetSubStatus sets status line(s), rendered under the bar, like name of
+// the file being processed. Empty status removes them.
+func (b *Bar) SetSubStatus(status string) {
+	select {
+	case b.operateState <- func(s *bState) { s.subStatus = status }:
+	case <-b.done:
+	}
+}
+
+// SetPriority changes bar's order position, same as
+// Progress.UpdateBarPriority. Zero is highest priority, i.e. bar will be
+// on top.
+func (b *Bar) SetPriority(priority int) {
+	b.container.UpdateBarPriority(b, priority)
+}
+
+// Show makes hidden bar to occupy a line again, see BarHidden. Bar, which
+// is queued after another one, is shown, once started.
+func (b *Bar) Show() {
+	select {
+	case b.container.operateState <- func(s *pState) { s.showBar(b) }:
+	case <-b.container.done:
+	}
+}
+
+// Hide frees the bar's line, until Show is called. Hidden bar keeps running,
+// but isn't rendered, so it can't complete until shown. Has no effect on
+// completed bar, once its final frame has been rendered.
+func (b *Bar) Hide() {
+	select {
+	case b.container.operateState <- func(s *pState) { s.hideBar(b) }:
+	case <-b.container.done:
+	}
+}
+
+// Fail marks the bar as failed and completes it, regardless of its current
+// progress. Failed bars are counted by aggregate bar, see AddAggregateBar.
+func (b *Bar) Fail() {
+	select {
+	case b.operateState <- func(s *bState) {
+		s.failed = true
+		s.toComplete = true
+	}:
+	case <-b.done:
+	}
+}
+
+// AbortWithReason aborts the bar, without removing it, like Progress.Abort
+// does, recording provided reason, which is exposed to decorators via
+// decor.Statistics.AbortReason, see decor.OnAbort. Nil reason means
+// ErrAborted.
+func (b *Bar) AbortWithReason(err error) {
+	b.abort(err, false)
+}
+
+// Abort aborts the bar, like Progress.Abort does, removing it, if drop is
+// true. Aborted bar isn't waited for by Progress.Wait. Kept bar is rendered
+// with abort mark in place of its tip, see BarAbortMark.
+func (b *Bar) Abort(drop bool) {
+	b.abort(nil, drop)
+}
+
+func (b *Bar) abort(err error, drop bool) {
+	select {
+	case b.operateState <- func(s *bState) {
+		if err == nil {
+			err = ErrAborted
+		}
+		s.abort(err)
+	}:
+		b.container.Abort(b, drop)
+	case <-b.done:
+	}
+}
+
+// setAbortReason marks the bar as aborted with provided reason, leaving
+// its removal to the caller. Safe to call from master Progress goroutine.
+func (b *Bar) setAbortReason(reason error) {
+	select {
+	case b.operateState <- func(s *bState) { s.abort(reason) }:
+	case <-b.done:
+	}
+}
+
+// completing reports whether the bar has reached its complete state.
+func (b *Bar) completing() bool {
+	result := make(chan bool, 1)
+	select {
+	case b.operateState <- func(s *bState) { result <- s.toComplete }:
+		return <-result
+	case <-b.done:
+		return true
+	}
+}
+
+func (b *Bar) abortError() error {
+	result := make(chan error, 1)
+	select {
+	case b.operateState <- func(s *bState) { result <- s.abortError() }:
+		return <-result
+	case <-b.done:
+		return b.cacheState.abortError()
+	}
+}
+
 // Completed reports whether the bar is in completed state.
 func (b *Bar) Completed() bool {
 	// omit select here, because primary usage of the method is for loop
@@ -234,12 +680,12 @@ func (b *Bar) Completed() bool {
 	return <-b.boolCh
 }
 
-func (b *Bar) wSyncTable() [][]chan int {
+func (b *Bar) wSyncTable() (int, [][]chan int) {
 	select {
-	case b.operateState <- func(s *bState) { b.syncTableCh <- s.wSyncTable() }:
-		return <-b.syncTableCh
+	case b.operateState <- func(s *bState) { b.int64Ch <- int64(s.id); b.syncTableCh <- s.wSyncTable() }:
+		return int(<-b.int64Ch), <-b.syncTableCh
 	case <-b.done:
-		return b.cacheState.wSyncTable()
+		return b.cacheState.id, b.cacheState.wSyncTable()
 	}
 }
 
@@ -252,6 +698,11 @@ func (b *Bar) serve(wg *sync.WaitGroup, s *bState, cancel <-chan struct{}) {
 		case b.boolCh <- s.toComplete:
 		case <-cancel:
 			s.toComplete = true
+			reason := ErrCancelled
+			if s.cancelReason != nil && s.cancelReason() != nil {
+				reason = s.cancelReason()
+			}
+			s.abort(reason)
 			cancel = nil
 		case <-b.shutdown:
 			b.cacheState = s
@@ -264,71 +715,246 @@ func (b *Bar) serve(wg *sync.WaitGroup, s *bState, cancel <-chan struct{}) {
 	}
 }
 
-func (b *Bar) render(debugOut io.Writer, tw int) {
+func (b *Bar) render(debugOut io.Writer, tw int, reuseDecor bool) {
 	select {
-	case b.operateState <- func(s *bState) {
-		defer func() {
-			// recovering if user defined decorator panics for example
-			if p := recover(); p != nil {
-				s.panicMsg = fmt.Sprintf("panic: %v", p)
-				fmt.Fprintf(debugOut, "%s %s bar id %02d %v\n", "[mpb]", time.Now(), s.id, s.panicMsg)
-				b.frameReaderCh <- &frameReader{
-					Reader:     strings.NewReader(fmt.Sprintf(fmt.Sprintf("%%.%ds\n", tw), s.panicMsg)),
-					toShutdown: true,
-				}
-			}
-		}()
-		r := s.draw(tw)
-		if s.newLineExtendFn != nil {
-			b.bufNL.Reset()
-			s.newLineExtendFn(b.bufNL, s.completeFlushed)
-			r = io.MultiReader(r, b.bufNL)
-		}
-		b.frameReaderCh <- &frameReader{
-			Reader:           r,
-			toShutdown:       s.toComplete && !s.completeFlushed,
-			removeOnComplete: s.removeOnComplete,
-		}
-		s.completeFlushed = s.toComplete
-	}:
+	case b.operateState <- func(s *bState) { b.renderFrame(s, debugOut, tw, reuseDecor) }:
 	case <-b.done:
-		s := b.cacheState
-		r := s.draw(tw)
-		if s.newLineExtendFn != nil {
-			b.bufNL.Reset()
-			s.newLineExtendFn(b.bufNL, s.completeFlushed)
-			r = io.MultiReader(r, b.bufNL)
+		b.renderCache(tw)
+	}
+}
+
+// renderFrame is called by the bar's goroutine.
+func (b *Bar) renderFrame(s *bState, debugOut io.Writer, tw int, reuseDecor bool) {
+	select {
+	case <-b.aborted:
+		s.abort(ErrAborted)
+	default:
+	}
+	s.drainShards()
+	// bar's completion frame is always decorated
+	s.reuseDecor = reuseDecor && !s.toComplete
+	defer func() {
+		// recovering if user defined decorator panics for example
+		if p := recover(); p != nil {
+			s.finishSync()
+			s.panicMsg = fmt.Sprintf("panic: %v", p)
+			fmt.Fprintf(debugOut, "%s %s bar id %02d %v\n", "[mpb]", time.Now(), s.id, s.panicMsg)
+			b.frameReaderCh <- &frameReader{
+				Reader:     strings.NewReader(fmt.Sprintf(fmt.Sprintf("%%.%ds\n", tw), s.panicMsg)),
+				err:        fmt.Errorf("bar id %02d %s", s.id, s.panicMsg),
+				toShutdown: true,
+			}
+		}
+	}()
+	r := b.withExtension(s, s.draw(tw), tw)
+	frame := &b.frame
+	*frame = frameReader{
+		Reader:           r,
+		toShutdown:       s.toComplete && !s.completeFlushed && !s.aggregate,
+		removeOnComplete: s.removeOnComplete,
+	}
+	if !s.aborted {
+		// cancelled bar is shutdown like completed one, but isn't complete
+		frame.onFinalFrame = s.onFinalFrame
+	}
+	select {
+	case <-b.aborted:
+		// already reported by Progress.Abort
+	default:
+		if frame.toShutdown && s.aborted {
+			frame.err = s.abortError()
+		}
+	}
+	b.frameReaderCh <- frame
+	s.completeFlushed = s.toComplete
+}
+
+func (b *Bar) renderCache(tw int) {
+	s := b.cacheState
+	b.frameReaderCh <- &frameReader{Reader: b.withExtension(s, s.draw(tw), tw)}
+}
+
+// withExtension appends lines, which go under the bar's main line, to r.
+func (b *Bar) withExtension(s *bState, r io.Reader, termWidth int) io.Reader {
+	if !s.extended() {
+		return r
+	}
+	if b.bufNL == nil {
+		b.bufNL = new(bytes.Buffer)
+	}
+	b.bufNL.Reset()
+	s.extend(b.bufNL, termWidth)
+	return io.MultiReader(r, b.bufNL)
+}
+
+// extended reports whether the bar has lines under its main line.
+func (s *bState) extended() bool {
+	return s.newLineExtendFn != nil || s.extender != nil || s.subStatus != ""
+}
+
+// extend writes lines under the bar's main line to w, see BarNewLineExtend,
+// BarExtender and Bar.SetSubStatus. Must be called after draw, as it
+// reuses statistics of the frame.
+func (s *bState) extend(w io.Writer, termWidth int) {
+	if s.newLineExtendFn != nil {
+		s.newLineExtendFn(w, s.completeFlushed)
+	}
+	if s.extender != nil {
+		writeLines(w, s.extender(&s.stat), termWidth)
+	}
+	writeLines(w, s.subStatus, termWidth)
+}
+
+// writeLines writes lines to w, each cut down to termWidth, like the bar's
+// main line, so none of them overflows onto the next terminal row.
+func writeLines(w io.Writer, lines string, termWidth int) {
+	if lines == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(lines, "\n"), "\n") {
+		io.WriteString(w, internal.Truncate(line, termWidth, "…"))
+		io.WriteString(w, "\n")
+	}
+}
+
+// abort marks the bar aborted with provided reason,
+// unless it has been marked with another one already.
+func (s *bState) abort(reason error) {
+	s.aborted = true
+	if s.abortReason == nil {
+		s.abortReason = reason
+	}
+}
+
+// abortError describes the bar's abort, as reported by Progress.Close.
+func (s *bState) abortError() error {
+	reason := s.abortReason
+	if reason == nil {
+		reason = ErrAborted
+	}
+	return fmt.Errorf("bar id %02d aborted: %v", s.id, reason)
+}
+
+func (s *bState) incrBy(n int64, wdd ...time.Duration) {
+	if n < 0 {
+		if s.toComplete {
+			return
+		}
+		if s.current+n < 0 {
+			n = -s.current
 		}
-		b.frameReaderCh <- &frameReader{Reader: r}
+	}
+	prev := s.current
+	s.current += n
+	if s.current >= s.total {
+		s.current = s.total
+		s.toComplete = !s.finishing
+	}
+	// amount beyond total isn't done, so it's not reported
+	n = s.current - prev
+	if n == 0 {
+		return
+	}
+	if n > 0 {
+		// refilled amount has been done before, so it's not reported
+		n -= s.refilledBy(prev, s.current)
+		if n == 0 {
+			return
+		}
+		// ewma based decorators measure time per item, rolled back
+		// amount would make it negative, so it's not reported
+		for _, ar := range s.amountReceivers {
+			ar.NextAmount(clampInt(n), wdd...)
+		}
+	}
+	if s.etaEstimator != nil {
+		s.updateEstimator(n, wdd...)
 	}
 }
 
+// refilledBy returns part of progress from prev to current, which is below
+// refill point.
+func (s *bState) refilledBy(prev, current int64) int64 {
+	if s.refill == nil || prev >= s.refill.till {
+		return 0
+	}
+	if current > s.refill.till {
+		current = s.refill.till
+	}
+	return current - prev
+}
+
+// refilled returns amount of progress, which is below refill point.
+func (s *bState) refilled() int64 {
+	return s.refilledBy(0, s.current)
+}
+
 func (s *bState) draw(termWidth int) io.Reader {
-	defer s.bufA.WriteByte('\n')
+	flash := s.color && s.toComplete && s.flashCount < s.flashFrames
+	defer func() {
+		if flash {
+			s.bufA.WriteString(sgrReset)
+		}
+		s.bufA.WriteByte('\n')
+	}()
 
 	if s.panicMsg != "" {
+		s.decorated = 0
+		s.finishSync()
 		return strings.NewReader(fmt.Sprintf(fmt.Sprintf("%%.%ds\n", termWidth), s.panicMsg))
 	}
 
-	stat := newStatistics(s)
+	if flash {
+		s.flashCount++
+		return io.MultiReader(strings.NewReader(sgrInverse), s.drawSections(termWidth))
+	}
+	return s.drawSections(termWidth)
+}
 
-	for _, d := range s.pDecorators {
-		s.bufP.WriteString(d.Decor(stat))
+func (s *bState) drawSections(termWidth int) io.Reader {
+	s.trackETA()
+	s.trackSpeed()
+	if s.toComplete && s.phase != "" {
+		s.switchPhase("", s.now())
 	}
+	stat := &s.stat
+	s.fillStatistics(stat)
 
-	for _, d := range s.aDecorators {
-		s.bufA.WriteString(d.Decor(stat))
+	if s.completeMsgFn != nil && s.toComplete {
+		// decorators aren't called, but must not block width sync
+		s.decorated = 0
+		s.finishSync()
+		msg := internal.Truncate(s.completeMsgFn(stat), termWidth, "")
+		return io.MultiReader(strings.NewReader(msg), s.bufA)
+	}
+	if s.finishing && s.current >= s.total {
+		s.finishingFrame++
 	}
 
-	prependCount := utf8.RuneCount(s.bufP.Bytes())
-	appendCount := utf8.RuneCount(s.bufA.Bytes())
+	s.decorated = 0
+	prependCount := s.decorate(s.bufP, s.pDecorators, &s.pCache, stat)
+	appendCount := s.decorate(s.bufA, s.aDecorators, &s.aCache, stat)
 
 	if s.barClearOnComplete && s.completeFlushed {
-		return io.MultiReader(s.bufP, s.bufA)
+		return s.sections.reset(s.bufP, s.bufA)
+	}
+
+	if s.spinner {
+		s.drawSpinner()
+		first, second := s.bufP, s.bufB
+		if s.spinnerPos == SpinnerOnLeft {
+			first, second = s.bufB, s.bufP
+		}
+		// spinner on right is moved to bufA, so appendCount is stale
+		totalCount := prependCount + internal.DisplayWidthBytes(s.bufB.Bytes()) + internal.DisplayWidthBytes(s.bufA.Bytes())
+		if totalCount > termWidth {
+			return s.truncate(termWidth, first, second, s.bufA)
+		}
+		return s.sections.reset(first, second, s.bufA)
 	}
 
 	s.fillBar(s.width)
-	barCount := utf8.RuneCount(s.bufB.Bytes())
+	barCount := internal.DisplayWidthBytes(s.bufB.Bytes())
 	totalCount := prependCount + barCount + appendCount
 	if spaceCount := 0; totalCount > termWidth {
 		if !s.trimLeftSpace {
@@ -337,15 +963,97 @@ func (s *bState) draw(termWidth int) io.Reader {
 		if !s.trimRightSpace {
 			spaceCount++
 		}
-		s.fillBar(termWidth - prependCount - appendCount - spaceCount)
+		width := termWidth - prependCount - appendCount - spaceCount
+		if s.wrapMinWidth > 0 && width < s.wrapMinWidth {
+			s.wrap(termWidth, prependCount, appendCount+spaceCount)
+			return s.sections.reset(s.bufP, s.bufB, s.bufA)
+		}
+		s.fillBar(width)
+		if prependCount+internal.DisplayWidthBytes(s.bufB.Bytes())+appendCount > termWidth {
+			return s.truncate(termWidth, s.bufP, s.bufB, s.bufA)
+		}
 	}
 
-	return io.MultiReader(s.bufP, s.bufB, s.bufA)
+	return s.sections.reset(s.bufP, s.bufB, s.bufA)
+}
+
+// decorate writes output of decorators ds to buf and keeps it in cache.
+// Each decorator is let to finish its width sync, even if it hasn't called
+// FormatMsg. If s.reuseDecor is set, output from cache is written instead,
+// without calling decorators at all. Returns display width of the output.
+func (s *bState) decorate(buf *bytes.Buffer, ds []decor.Decorator, cache *[]string, stat *decor.Statistics) (width int) {
+	if s.reuseDecor && len(*cache) == len(ds) {
+		for i, d := range ds {
+			str := (*cache)[i]
+			w := internal.DisplayWidth(str)
+			d.FinishSync(w)
+			s.decorated++
+			buf.WriteString(str)
+			width += w
+		}
+		return width
+	}
+	*cache = (*cache)[:0]
+	for _, d := range ds {
+		str := d.Decor(stat)
+		w := internal.DisplayWidth(str)
+		d.FinishSync(w)
+		s.decorated++
+		buf.WriteString(str)
+		*cache = append(*cache, str)
+		width += w
+	}
+	return width
+}
+
+// finishSync finishes width sync of decorators, which haven't been
+// decorated during current render cycle, so no other bar blocks on them.
+func (s *bState) finishSync() {
+	for i := s.decorated; i < len(s.pDecorators)+len(s.aDecorators); i++ {
+		if i < len(s.pDecorators) {
+			s.pDecorators[i].FinishSync(0)
+		} else {
+			s.aDecorators[i-len(s.pDecorators)].FinishSync(0)
+		}
+	}
+	s.decorated = len(s.pDecorators) + len(s.aDecorators)
+}
+
+// truncate cuts the whole line down to termWidth, so it never overflows
+// onto the next terminal row, which would break the redraw.
+func (s *bState) truncate(termWidth int, sections ...*bytes.Buffer) io.Reader {
+	var line string
+	for _, buf := range sections {
+		line += buf.String()
+		buf.Reset()
+	}
+	s.bufA.WriteString(internal.Truncate(line, termWidth, "…"))
+	return s.bufA
+}
+
+// wrap moves bar section with append decorators onto continuation line,
+// any line, which is still longer than termWidth, is wrapped as well.
+func (s *bState) wrap(termWidth, prependCount, appendCount int) {
+	if prependCount > 0 {
+		wrapped := internal.Wrap(s.bufP.String(), termWidth)
+		s.bufP.Reset()
+		s.bufP.WriteString(wrapped)
+		s.bufP.WriteByte('\n')
+	}
+
+	s.fillBar(termWidth - appendCount)
+	if internal.DisplayWidthBytes(s.bufB.Bytes())+appendCount <= termWidth {
+		return
+	}
+	wrapped := internal.Wrap(s.bufB.String()+s.bufA.String(), termWidth)
+	s.bufB.Reset()
+	s.bufA.Reset()
+	s.bufA.WriteString(wrapped)
 }
 
 func (s *bState) fillBar(width int) {
 	defer func() {
-		s.bufB.WriteRune(s.runes[rRight])
+		s.bufB.WriteString(s.style.Right)
 		if !s.trimRightSpace {
 			s.bufB.WriteByte(' ')
 		}
@@ -355,42 +1063,119 @@ func (s *bState) fillBar(width int) {
 	if !s.trimLeftSpace {
 		s.bufB.WriteByte(' ')
 	}
-	s.bufB.WriteRune(s.runes[rLeft])
-	if width <= 2 {
+	s.bufB.WriteString(s.style.Left)
+	leftWidth := internal.DisplayWidth(s.style.Left)
+	rightWidth := internal.DisplayWidth(s.style.Right)
+	if width <= leftWidth+rightWidth {
 		return
 	}
 
-	// bar s.width without leftEnd and rightEnd runes
-	barWidth := width - 2
+	// bar s.width without left and right edges
+	barWidth := int64(width - leftWidth - rightWidth)
 
-	completedWidth := internal.Percentage(s.total, s.current, int64(barWidth))
+	if len(s.chunks) > 0 {
+		s.fillChunks(barWidth)
+		return
+	}
 
-	if s.refill != nil {
-		till := internal.Percentage(s.total, s.refill.till, int64(barWidth))
-		// append refill rune
-		var i int64
-		for i = 0; i < till; i++ {
-			s.bufB.WriteRune(s.refill.char)
+	if s.aggregate && s.aggrCounts.failedTotal > 0 {
+		s.fillFailed(barWidth)
+		return
+	}
+
+	style := s.style
+	if s.reverse {
+		// partially filled cells are left aligned, so can't lead leftwards
+		style.Smooth = nil
+	}
+	completedWidth, partial := style.progress(s.total, s.current, barWidth)
+	if s.total <= 0 && s.toComplete {
+		// nothing to do is done
+		completedWidth, partial = barWidth, ""
+	}
+
+	fillColor, emptyColor := s.styleColors()
+
+	// leading edge of filled part is the tip, unless the bar is full or
+	// partially filled cell stands for it
+	var tip string
+	if markWidth := int64(internal.DisplayWidth(s.abortMark)); s.aborted && markWidth > 0 && markWidth <= barWidth {
+		// abort mark stands for the tip, wherever the bar has stopped
+		partial, tip = "", s.abortMark
+		if completedWidth < markWidth {
+			completedWidth = markWidth
 		}
-		for i = till; i < completedWidth; i++ {
-			s.bufB.WriteRune(s.runes[rFill])
+	} else if partial != "" {
+		// no tip
+	} else if completedWidth < barWidth && completedWidth > 0 {
+		tip = s.style.Tip
+	} else if s.finishing && s.current >= s.total && completedWidth > 0 {
+		tip = string(finishingFrames[s.finishingFrame%len(finishingFrames)])
+	}
+	tipWidth := int64(internal.DisplayWidth(tip))
+	if tipWidth > completedWidth {
+		tip, tipWidth = "", 0
+	}
+	bodyWidth := completedWidth - tipWidth
+
+	var refillWidth int64
+	var refillCell, refillColor string
+	if s.refill != nil {
+		refillWidth = internal.Percentage(s.total, s.refill.till, barWidth)
+		if refillWidth > bodyWidth {
+			refillWidth = bodyWidth
 		}
-	} else {
-		var i int64
-		for i = 0; i < completedWidth; i++ {
-			s.bufB.WriteRune(s.runes[rFill])
+		refillCell = string(s.refill.char)
+		if s.color {
+			refillColor = s.refill.color
 		}
 	}
 
-	if completedWidth < int64(barWidth) && completedWidth > 0 {
-		_, size := utf8.DecodeLastRune(s.bufB.Bytes())
-		s.bufB.Truncate(s.bufB.Len() - size)
-		s.bufB.WriteRune(s.runes[rTip])
+	emptyWidth := barWidth - completedWidth
+	if partial != "" {
+		emptyWidth--
 	}
 
-	for i := completedWidth; i < int64(barWidth); i++ {
-		s.bufB.WriteRune(s.runes[rEmpty])
+	if s.reverse {
+		s.fillCells(emptyWidth, "", s.style.Empty, "", emptyColor)
+		s.fillCells(bodyWidth-refillWidth, mirror(tip), s.style.Fill, "", fillColor)
+		s.fillCells(refillWidth, "", refillCell, "", refillColor)
+		return
 	}
+	s.fillCells(refillWidth, "", refillCell, "", refillColor)
+	s.fillCells(bodyWidth-refillWidth, "", s.style.Fill, tip+partial, fillColor)
+	s.fillCells(emptyWidth, "", s.style.Empty, "", emptyColor)
+}
+
+// fillCells writes n cells of cell, between head and tail, colored by
+// color, if it isn't empty.
+func (s *bState) fillCells(n int64, head, cell, tail, color string) {
+	if n <= 0 && head == "" && tail == "" {
+		return
+	}
+	if color != "" {
+		s.bufB.WriteString(color)
+	}
+	s.bufB.WriteString(head)
+	for i := int64(0); i < n; i++ {
+		s.bufB.WriteString(cell)
+	}
+	s.bufB.WriteString(tail)
+	if color != "" {
+		s.bufB.WriteString(sgrReset)
+	}
+}
+
+// styleColors returns SGR sequences for filled and empty parts of the bar,
+// according to BarStyleColor, or empty ones, if color is disabled.
+func (s *bState) styleColors() (fill, empty string) {
+	if !s.color {
+		return "", ""
+	}
+	if (s.aborted || s.failed) && s.abortColor != "" {
+		return s.abortColor, s.emptyColor
+	}
+	return s.fillColor, s.emptyColor
 }
 
 func (s *bState) wSyncTable() [][]chan int {
@@ -416,18 +1201,137 @@ func (s *bState) wSyncTable() [][]chan int {
 }
 
 func newStatistics(s *bState) *decor.Statistics {
-	return &decor.Statistics{
-		ID:        s.id,
-		Completed: s.completeFlushed,
-		Total:     s.total,
-		Current:   s.current,
+	st := new(decor.Statistics)
+	s.fillStatistics(st)
+	return st
+}
+
+// fillStatistics overwrites st with the bar's statistics, so draw reuses
+// single Statistics across frames.
+func (s *bState) fillStatistics(st *decor.Statistics) {
+	*st = decor.Statistics{
+		ID:             s.id,
+		Completed:      s.completeFlushed,
+		Total:          s.total,
+		Current:        s.current,
+		ETA:            s.estimate(),
+		Attempts:       s.attempts,
+		Finishing:      s.finishing && s.current >= s.total,
+		ReadOps:        s.readOps,
+		ReadBytes:      s.readBytes,
+		SourceReadOps:  s.sourceReadOps,
+		Refilled:       s.refilled(),
+		Locale:         s.locale,
+		WriteOps:       s.writeOps,
+		WriteBytes:     s.writeBytes,
+		Color:          s.color,
+		ETAError:       s.etaError,
+		Succeeded:      s.aggrCounts.succeeded,
+		Failed:         s.aggrCounts.failed,
+		Aborted:        s.aggrCounts.aborted + boolToInt(s.aborted),
+		AbortReason:    s.abortReason,
+		Paused:         !s.pausedAt.IsZero(),
+		PhaseDurations: s.phaseStatistics(),
+		CurrentSpeed:   s.currentSpeed(),
+		AverageSpeed:   s.averageSpeed(),
+		SpeedSampled:   s.speedSampled(),
+		Detached:       s.detached,
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+const (
+	maxInt = int(^uint(0) >> 1)
+	minInt = -maxInt - 1
+)
+
+// clampInt converts n to int, saturating instead of wrapping around, where
+// int is 32-bit.
+func clampInt(n int64) int {
+	switch {
+	case n > int64(maxInt):
+		return maxInt
+	case n < int64(minInt):
+		return minInt
+	}
+	return int(n)
+}
+
+func (s *bState) estimate() time.Duration {
+	if s.aggregate {
+		return s.eta
+	}
+	if s.etaEstimator != nil {
+		return s.etaEstimator.Eta(clampInt(s.total - s.current))
+	}
+	// refilled amount has been done before, so it doesn't tell the rate
+	refilled := s.refilled()
+	return averageETA(s.elapsed(), s.total-refilled, s.current-refilled)
+}
+
+// updateEstimator feeds etaEstimator with increment by n, which duration
+// is wdd, if provided, otherwise time since previous increment.
+func (s *bState) updateEstimator(n int64, wdd ...time.Duration) {
+	now := s.now()
+	last := s.lastIncr
+	if last.IsZero() {
+		last = s.startTime
 	}
+	dur := now.Sub(last)
+	for _, wd := range wdd {
+		dur = wd
+	}
+	s.lastIncr = now
+	s.etaEstimator.Update(clampInt(n), dur)
 }
 
-func strToBarRunes(format string) (array barRunes) {
-	for i, n := 0, 0; len(format) > 0; i++ {
-		array[i], n = utf8.DecodeRuneInString(format)
-		format = format[n:]
+// trackETA samples predicted finish time once per frame, while the bar is
+// running. On complete, mean absolute error of samples is calculated.
+// If samples reach maxETASamples, every second one is dropped and the
+// sampling rate is halved, so memory use is bounded on long runs.
+func (s *bState) trackETA() {
+	if s.etaErrorDone || s.spinner {
+		return
+	}
+	now := s.now()
+	if s.toComplete {
+		var sum time.Duration
+		for _, finish := range s.etaSamples {
+			diff := finish.Sub(now)
+			if diff < 0 {
+				diff = -diff
+			}
+			sum += diff
+		}
+		if len(s.etaSamples) > 0 {
+			s.etaError = sum / time.Duration(len(s.etaSamples))
+		}
+		s.etaSamples = nil
+		s.etaErrorDone = true
+		return
+	}
+	if s.current == 0 || !s.pausedAt.IsZero() {
+		return
+	}
+	if s.etaStep == 0 {
+		s.etaStep = 1
+	}
+	s.etaFrame++
+	if s.etaFrame%s.etaStep != 0 {
+		return
+	}
+	if len(s.etaSamples) == maxETASamples {
+		for i := 0; i < maxETASamples/2; i++ {
+			s.etaSamples[i] = s.etaSamples[2*i+1]
+		}
+		s.etaSamples = s.etaSamples[:maxETASamples/2]
+		s.etaStep *= 2
 	}
-	return
+	s.etaSamples = append(s.etaSamples, now.Add(s.estimate()))
 }
@@ -0,0 +1,44 @@
+//+build go1.7
+
+package mpb
+
+import "context"
+
+// Context returns a copy of parent, which is cancelled, when the bar is
+// aborted or the container is cancelled, see WithCancel and WithContext.
+// Normal completion of the bar doesn't cancel returned context.
+func (b *Bar) Context(parent context.Context) context.Context {
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-b.aborted:
+			cancel()
+		case <-b.cancel:
+			cancel()
+		case <-b.done:
+			// Abort and container cancel, both end up closing done
+			select {
+			case <-b.aborted:
+				cancel()
+			case <-b.cancel:
+				cancel()
+			default:
+			}
+		case <-ctx.Done():
+		}
+	}()
+	return ctx
+}
+
+// AbortOnContext aborts the bar, without removing it, once ctx is done,
+// unless the bar is done by then. Context's error becomes abort reason,
+// see AbortWithReason.
+func (b *Bar) AbortOnContext(ctx context.Context) {
+	go func() {
+		select {
+		case <-ctx.Done():
+			b.AbortWithReason(ctx.Err())
+		case <-b.done:
+		}
+	}()
+}
@@ -2,8 +2,10 @@ package mpb
 
 import (
 	"io"
+	"time"
 
 	"github.com/vbauerster/mpb/decor"
+	"github.com/vbauerster/mpb/internal"
 )
 
 // BarOption is a function option which changes the default behavior of a bar,
@@ -20,6 +22,9 @@ func AppendDecorators(appenders ...decor.Decorator) BarOption {
 			if sl, ok := decorator.(decor.ShutdownListener); ok {
 				s.shutdownListeners = append(s.shutdownListeners, sl)
 			}
+			if pl, ok := decorator.(decor.PauseListener); ok {
+				s.pauseListeners = append(s.pauseListeners, pl)
+			}
 			s.aDecorators = append(s.aDecorators, decorator)
 		}
 	}
@@ -35,6 +40,9 @@ func PrependDecorators(prependers ...decor.Decorator) BarOption {
 			if sl, ok := decorator.(decor.ShutdownListener); ok {
 				s.shutdownListeners = append(s.shutdownListeners, sl)
 			}
+			if pl, ok := decorator.(decor.PauseListener); ok {
+				s.pauseListeners = append(s.pauseListeners, pl)
+			}
 			s.pDecorators = append(s.pDecorators, decorator)
 		}
 	}
@@ -86,6 +94,27 @@ func BarReplaceOnComplete(runningBar *Bar) BarOption {
 	}
 }
 
+// BarQueueAfter makes the bar to stay hidden, until provided bar completes
+// or is aborted, then to take over its line, i.e. provided bar is removed
+// once complete, as if it had BarRemoveOnComplete option set. Intended for sequential stages, like
+// download, extract and install, each queued after the previous one.
+// Any number of bars can be queued after the same bar.
+func BarQueueAfter(b *Bar) BarOption {
+	return func(s *bState) {
+		s.runningBar = b
+		s.takeOver = true
+	}
+}
+
+// BarHidden makes the bar to stay hidden, i.e. to not occupy a line, until
+// Bar.Show is called. Intended for bars, registered up front for queued
+// jobs, which are shown once their job starts.
+func BarHidden() BarOption {
+	return func(s *bState) {
+		s.hidden = true
+	}
+}
+
 // BarClearOnComplete is a flag, if set will clear bar section on complete event.
 // If you need to remove a whole bar line, refer to BarRemoveOnComplete.
 func BarClearOnComplete() BarOption {
@@ -94,6 +123,25 @@ func BarClearOnComplete() BarOption {
 	}
 }
 
+// BarMessageOnComplete replaces whole bar line with a message, returned
+// by fn, once the bar is complete. Like "archive.tar.gz downloaded in 3.2s".
+func BarMessageOnComplete(fn decor.DecorFunc) BarOption {
+	return func(s *bState) {
+		s.completeMsgFn = fn
+	}
+}
+
+// BarOnFinalFrame sets fn to be called exactly once, right after the frame
+// with bar's completed state has been flushed to the output. Every completed
+// bar is guaranteed to have such frame flushed, before it is done. Aborted
+// bars have no final frame, so fn isn't called for them. fn is called from
+// the container's goroutine, so it must not block nor call Progress methods.
+func BarOnFinalFrame(fn func()) BarOption {
+	return func(s *bState) {
+		s.onFinalFrame = fn
+	}
+}
+
 // BarPriority sets bar's priority.
 // Zero is highest priority, i.e. bar will be on top.
 // If `BarReplaceOnComplete` option is supplied, this option is ignored.
@@ -111,14 +159,177 @@ func BarNewLineExtend(efn func(w io.Writer, completed bool)) BarOption {
 	}
 }
 
+// BarExtender takes fn, which is called each render cycle, its output is
+// rendered under the bar as one or more lines. Unlike BarNewLineExtend, fn
+// gets the bar's statistics. Lines are added and removed along with the
+// bar.
+func BarExtender(fn decor.DecorFunc) BarOption {
+	return func(s *bState) {
+		s.extender = fn
+	}
+}
+
+// BarClock makes the bar, its proxy readers and writers and its time
+// measuring decorators, like decor.Elapsed, to tell time by provided
+// clock, see decor.ClockReceiver.
+func BarClock(c Clock) BarOption {
+	return func(s *bState) {
+		s.clock = c
+	}
+}
+
+// BarSpeedWindow sets time window, decor.Statistics.CurrentSpeed is
+// measured over. Default is 5s.
+func BarSpeedWindow(d time.Duration) BarOption {
+	return func(s *bState) {
+		s.speedWindow = d
+	}
+}
+
+// BarWrapDecorators enables decorators wrapping, instead of shrinking the
+// bar section. If bar section has to shrink below minBarWidth to fit the
+// terminal, prepend decorators are rendered on their own line(s), and bar
+// section with append decorators goes onto continuation line. Any line,
+// which is still longer than terminal width, is wrapped as well.
+func BarWrapDecorators(minBarWidth int) BarOption {
+	return func(s *bState) {
+		s.wrapMinWidth = minBarWidth
+	}
+}
+
+// BarFlashOnComplete renders whole bar line inverted for provided number of
+// frames, once the bar is complete. Draws the eye to finished bars, when there
+// are dozens of active ones. Has no effect, if color is disabled.
+func BarFlashOnComplete(frames int) BarOption {
+	return func(s *bState) {
+		s.flashFrames = frames
+	}
+}
+
+// BarStyleColor colors filled and empty parts of the bar with provided SGR
+// sequences, like decor.ColorGreen. Filled part is colored with abort one,
+// once the bar is aborted or failed. Empty sequence leaves the part as is.
+// Has no effect, if color is disabled.
+func BarStyleColor(fill, empty, abort string) BarOption {
+	return func(s *bState) {
+		s.fillColor = fill
+		s.emptyColor = empty
+		s.abortColor = abort
+	}
+}
+
+// BarAbortMark sets mark, like "✗", which is rendered in place of bar's tip,
+// once the bar is aborted and isn't removed. The mark is colored with abort
+// color, see BarStyleColor.
+func BarAbortMark(mark string) BarOption {
+	return func(s *bState) {
+		s.abortMark = mark
+	}
+}
+
+// BarCriticalPathETA makes aggregate bar to estimate its ETA by the slowest
+// remaining bar, instead of summed average of all bars. Summed average badly
+// underestimates completion time, if work is skewed among bars.
+// Effective for bar created with p.AddAggregateBar only.
+func BarCriticalPathETA() BarOption {
+	return func(s *bState) {
+		s.criticalPath = true
+	}
+}
+
+// BarSpinnerPosition sets spinner position, relative to the bar's decorators.
+// Spinner is rendered, while bar's total is unknown, i.e. it was added with
+// total <= 0 and no positive total has been set yet.
+func BarSpinnerPosition(pos SpinnerPosition) BarOption {
+	return func(s *bState) {
+		s.spinnerPos = pos
+	}
+}
+
+// BarLocale makes counter decorators of the bar, like decor.CountersNoUnit
+// and decor.Remaining, to group digits of numbers without unit according
+// to provided locale, e.g. "1,234,567 / 2,000,000" with decor.LocaleEnglish.
+func BarLocale(locale decor.Locale) BarOption {
+	return func(s *bState) {
+		s.locale = &locale
+	}
+}
+
+// BarSpinnerStyle overrides default "-\\|/" spinner frames, e.g. with
+// SpinnerBraille. Frames can be of any width, they're padded to the widest.
+func BarSpinnerStyle(frames []string) BarOption {
+	return func(s *bState) {
+		if len(frames) == 0 {
+			return
+		}
+		s.spinnerFrames = frames
+		s.spinnerWidth = 0
+		for _, frame := range frames {
+			if w := internal.DisplayWidth(frame); w > s.spinnerWidth {
+				s.spinnerWidth = w
+			}
+		}
+	}
+}
+
+// BarSpinnerInterval makes spinner to advance to next frame once per
+// provided interval, instead of each render cycle, so spinner speed
+// doesn't depend on refresh rate.
+func BarSpinnerInterval(d time.Duration) BarOption {
+	return func(s *bState) {
+		s.spinnerInterval = d
+	}
+}
+
+// BarWithStyle overrides container's bar style, see WithBarStyle.
+func BarWithStyle(style BarStyle) BarOption {
+	return barStyle(style)
+}
+
+// BarReverse makes the bar to fill from the right edge toward the left,
+// with the tip on the left side of filled part, mirrored, so "=>" turns
+// into "<=". Smooth cells of BarStyle aren't drawn, as they can't lead
+// leftwards. Useful for countdowns and paired bars, growing toward each
+// other.
+func BarReverse() BarOption {
+	return func(s *bState) {
+		s.reverse = true
+	}
+}
+
+func barAggregate() BarOption {
+	return func(s *bState) {
+		s.aggregate = true
+		s.spinner = false
+	}
+}
+
 func barWidth(w int) BarOption {
 	return func(s *bState) {
 		s.width = w
 	}
 }
 
-func barFormat(format string) BarOption {
+func barStyle(style BarStyle) BarOption {
+	return func(s *bState) {
+		s.style = style
+	}
+}
+
+func barIOCounter(counter *int64) BarOption {
+	return func(s *bState) {
+		s.ioCounter = counter
+	}
+}
+
+func barCancelReason(fn func() error) BarOption {
+	return func(s *bState) {
+		s.cancelReason = fn
+	}
+}
+
+func barColor(color bool) BarOption {
 	return func(s *bState) {
-		s.runes = strToBarRunes(format)
+		s.color = color
 	}
 }
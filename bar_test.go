@@ -2,9 +2,12 @@ package mpb_test
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -52,6 +55,94 @@ func TestBarID(t *testing.T) {
 	p.Wait()
 }
 
+func TestBarIncrInt64(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard))
+
+	// exceeds max int32
+	total := int64(8 << 30)
+	bar := p.AddBar(total)
+	bar.SetRefillInt64(total/2, '+')
+	bar.IncrInt64(total / 2)
+	bar.IncrInt64(total / 4)
+
+	if got := bar.Current(); got != total*3/4 {
+		t.Errorf("want current %d, got %d", total*3/4, got)
+	}
+
+	bar.IncrInt64(total / 4)
+	p.Wait()
+}
+
+func TestBarSetCurrent(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	bar := p.AddBar(100, BarTrim(), AppendDecorators(decor.CountersNoUnit("%d/%d")))
+	bar.SetCurrent(60)
+	bar.IncrBy(-20)
+	if got := bar.Current(); got != 40 {
+		t.Errorf("want current 40, got %d", got)
+	}
+	bar.IncrBy(-50)
+	if got := bar.Current(); got != 0 {
+		t.Errorf("want current 0, got %d", got)
+	}
+	bar.SetCurrent(150)
+	p.Wait()
+
+	if !strings.HasSuffix(buf.String(), "100/100\n") {
+		t.Errorf("unexpected last frame: %q", buf.String())
+	}
+}
+
+func TestBarShardedCounter(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	workers, perWorker := 64, 100
+	bar := p.AddBar(int64(workers*perWorker), BarTrim(), AppendDecorators(decor.CountersNoUnit("%d/%d")))
+	var wg sync.WaitGroup
+	for _, shard := range bar.ShardedCounter(workers) {
+		wg.Add(1)
+		go func(shard *Shard) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				shard.Increment()
+			}
+		}(shard)
+	}
+	wg.Wait()
+	p.Wait()
+
+	want := fmt.Sprintf("%d/%d\n", workers*perWorker, workers*perWorker)
+	if !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("want last frame to end with %q, got %q", want, buf.String())
+	}
+}
+
+func TestBarSetTotal(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	bar := p.AddBar(0, BarTrim(), AppendDecorators(decor.Percentage()))
+	bar.IncrBy(10)
+	time.Sleep(200 * time.Millisecond)
+
+	bar.SetTotal(20, false)
+	if got := bar.Current(); got != 10 {
+		t.Errorf("want current 10, got %d", got)
+	}
+	bar.IncrBy(10)
+	p.Wait()
+
+	// must not block, once the bar is done
+	bar.SetTotal(30, true)
+
+	if !strings.HasSuffix(buf.String(), "100 %\n") {
+		t.Errorf("unexpected last frame: %q", buf.String())
+	}
+}
+
 func TestBarSetRefill(t *testing.T) {
 	var buf bytes.Buffer
 
@@ -108,10 +199,13 @@ func TestBarPanics(t *testing.T) {
 	}
 }
 
-func panicDecorator(panicMsg string) decor.Decorator {
+func panicDecorator(panicMsg string, wcc ...decor.WC) decor.Decorator {
 	d := &decorator{
 		panicMsg: panicMsg,
 	}
+	for _, wc := range wcc {
+		d.WC = wc
+	}
 	d.Init()
 	return d
 }
@@ -127,3 +221,453 @@ func (d *decorator) Decor(st *decor.Statistics) string {
 	}
 	return d.FormatMsg("")
 }
+
+func TestBarReset(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf))
+
+	total := 100
+	bar := p.AddBar(int64(total), AppendDecorators(decor.Attempts(" try %d")))
+
+	bar.IncrBy(42)
+	bar.Reset()
+	if current := bar.Current(); current != 0 {
+		t.Errorf("Expected current: %d, got: %d\n", 0, current)
+	}
+
+	for i := 0; i < total; i++ {
+		bar.Increment()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	p.Wait()
+
+	if !strings.Contains(buf.String(), "try 2") {
+		t.Errorf("%q doesn't contain %q\n", buf.String(), "try 2")
+	}
+}
+
+func TestBarSyncableDecoratorNoFormatMsg(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard), WithRefreshRate(10*time.Millisecond))
+
+	for i := 0; i < 3; i++ {
+		bar := p.AddBar(100,
+			PrependDecorators(lazyDecorator(), decor.Percentage(decor.WCSyncWidth)),
+			AppendDecorators(panicDecorator("Upps!!!", decor.WCSyncWidth)),
+		)
+		go func() {
+			for i := 0; i < 100; i++ {
+				time.Sleep(time.Millisecond)
+				bar.Increment()
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("width sync deadlock")
+	}
+}
+
+// lazyDecorator has width sync enabled, but never calls FormatMsg
+func lazyDecorator() decor.Decorator {
+	d := &lazy{WC: decor.WCSyncWidth}
+	d.Init()
+	return d
+}
+
+type lazy struct {
+	decor.WC
+}
+
+func (d *lazy) Decor(st *decor.Statistics) string {
+	return "lazy"
+}
+
+func TestChunks(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard))
+	total := 1000
+	bar, writers := Chunks(p, int64(total), 3)
+	if len(writers) != 3 {
+		t.Fatalf("want 3 writers, got %d", len(writers))
+	}
+
+	var wg sync.WaitGroup
+	for _, w := range writers {
+		wg.Add(1)
+		go func(w io.Writer) {
+			defer wg.Done()
+			// writing more than chunk's size must not overflow into other chunks
+			for i := 0; i < total; i += 100 {
+				w.Write(make([]byte, 100))
+			}
+		}(w)
+	}
+	wg.Wait()
+	p.Wait()
+
+	if got := bar.Current(); got != int64(total) {
+		t.Errorf("want current %d, got %d", total, got)
+	}
+}
+
+func TestTwoStageBar(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+	bar := p.AddTwoStageBar("scanning", "processing", BarTrim())
+
+	for i := 0; i < 3; i++ {
+		bar.Found(1)
+	}
+	time.Sleep(300 * time.Millisecond)
+	bar.StartWork()
+	// no effect at stage two
+	bar.Found(10)
+	for i := 0; i < 3; i++ {
+		bar.Increment()
+	}
+	p.Wait()
+
+	out := buf.String()
+	if !strings.Contains(out, "scanning 3") {
+		t.Errorf("stage one label isn't rendered: %q", out)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, "processing") || strings.Contains(last, "-") {
+		t.Errorf("unexpected last frame: %q", last)
+	}
+}
+
+func TestTwoStageBarNothingFound(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend), WithWidth(30))
+	bar := p.AddTwoStageBar("scanning", "processing", BarTrim(),
+		AppendDecorators(decor.CountersNoUnit("%d/%d")))
+	bar.StartWork()
+	p.Wait()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got, want := lines[len(lines)-1], "processing[===============]0/0"; got != want {
+		t.Errorf("want last frame %q, got %q", want, got)
+	}
+}
+
+func TestBarSetPhase(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard), WithRefreshRate(10*time.Millisecond))
+
+	var phases map[string]time.Duration
+	bar := p.AddBar(100, AppendDecorators(decor.Any(func(st *decor.Statistics) string {
+		phases = st.PhaseDurations
+		return ""
+	})))
+	bar.SetPhase("fetch")
+	time.Sleep(100 * time.Millisecond)
+	bar.SetPhase("build")
+	time.Sleep(200 * time.Millisecond)
+	bar.IncrBy(100)
+	p.Wait()
+
+	if len(phases) != 2 {
+		t.Fatalf("want 2 phases, got %v", phases)
+	}
+	if phases["fetch"] < 100*time.Millisecond || phases["fetch"] >= 200*time.Millisecond {
+		t.Errorf("unexpected fetch duration %s", phases["fetch"])
+	}
+	if phases["build"] < 200*time.Millisecond {
+		t.Errorf("unexpected build duration %s", phases["build"])
+	}
+}
+
+func TestBarRemoveOnComplete(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard), WithRefreshRate(10*time.Millisecond))
+
+	removed := p.AddBar(10, BarRemoveOnComplete())
+	kept := p.AddBar(10)
+
+	removed.IncrBy(10)
+	time.Sleep(100 * time.Millisecond)
+
+	if count := p.BarCount(); count != 1 {
+		t.Errorf("BarCount want: 1, got: %d\n", count)
+	}
+
+	kept.IncrBy(10)
+	p.Wait()
+}
+
+func TestBarQueueAfter(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard), WithRefreshRate(10*time.Millisecond))
+
+	download := p.AddBar(10)
+	extract := p.AddBar(10, BarQueueAfter(download))
+	install := p.AddBar(10, BarQueueAfter(extract))
+	// queued after the same bar, which is going to be aborted
+	cleanup := p.AddBar(10, BarQueueAfter(extract))
+
+	if count := p.BarCount(); count != 1 {
+		t.Errorf("BarCount want: 1, got: %d\n", count)
+	}
+
+	download.IncrBy(10)
+	time.Sleep(100 * time.Millisecond)
+
+	if count := p.BarCount(); count != 1 {
+		t.Errorf("BarCount want: 1, got: %d\n", count)
+	}
+
+	p.Abort(extract, true)
+	time.Sleep(100 * time.Millisecond)
+
+	// download is done already, so it doesn't hold this one
+	late := p.AddBar(10, BarQueueAfter(download))
+	if count := p.BarCount(); count != 3 {
+		t.Errorf("BarCount want: 3, got: %d\n", count)
+	}
+
+	install.IncrBy(10)
+	cleanup.IncrBy(10)
+	late.IncrBy(10)
+	p.Wait()
+}
+
+func TestBarOnFinalFrame(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	var calls int
+	var flushed bool
+	bar := p.AddBar(10, BarTrim(),
+		AppendDecorators(decor.Percentage()),
+		BarOnFinalFrame(func() {
+			calls++
+			// called from container's goroutine, so buf is safe to read
+			flushed = strings.HasSuffix(buf.String(), "100 %\n")
+		}),
+	)
+	bar.IncrBy(10)
+	p.Wait()
+
+	if calls != 1 {
+		t.Errorf("want 1 call, got %d", calls)
+	}
+	if !flushed {
+		t.Error("final frame wasn't flushed before the hook call")
+	}
+}
+
+func TestBarSetRefillStyleDone(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard))
+	bar := p.AddBar(10)
+	bar.IncrBy(10)
+	p.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		bar.SetRefillStyle(5, '+', decor.ColorBlue)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("SetRefillStyle blocks on done bar")
+	}
+}
+
+func TestBarOnFinalFrameCancelled(t *testing.T) {
+	cancel := make(chan struct{})
+	p := New(WithOutput(ioutil.Discard), WithCancel(cancel))
+
+	var calls int
+	bar := p.AddBar(10, BarOnFinalFrame(func() { calls++ }))
+	bar.IncrBy(5)
+	close(cancel)
+	p.Wait()
+
+	if calls != 0 {
+		t.Errorf("want no calls for cancelled bar, got %d", calls)
+	}
+}
+
+func TestBarPauseResume(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	bar := p.AddBar(100, BarTrim(), PrependDecorators(decor.OnPause(decor.Name("running"), "paused")))
+	bar.IncrBy(50)
+	bar.Pause()
+	time.Sleep(300 * time.Millisecond)
+	bar.Resume()
+	time.Sleep(300 * time.Millisecond)
+	bar.IncrBy(50)
+	p.Wait()
+
+	out := buf.String()
+	paused := strings.LastIndex(out, "paused[")
+	if paused < 0 {
+		t.Fatalf("paused indicator isn't rendered: %q", out)
+	}
+	if !strings.Contains(out[paused:], "running[") {
+		t.Errorf("bar isn't resumed: %q", out)
+	}
+}
+
+func TestBarAbortWithReason(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	reason := errors.New("disk full")
+	withReason := p.AddBar(100, BarTrim(), PrependDecorators(decor.OnAbort(decor.Name("a:running"), "")))
+	bare := p.AddBar(100, BarTrim(), PrependDecorators(decor.OnAbort(decor.Name("b:running"), "b:failed")))
+	withReason.AbortWithReason(reason)
+	p.Abort(bare, false)
+	p.Wait()
+
+	out := buf.String()
+	if !strings.Contains(out, "disk full[") {
+		t.Errorf("abort reason isn't rendered: %q", out)
+	}
+	if !strings.Contains(out, "b:failed[") {
+		t.Errorf("abort message isn't rendered: %q", out)
+	}
+}
+
+func TestBarAbortDrop(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	kept := p.AddBar(100, BarTrim(), BarAbortMark("✗"), PrependDecorators(decor.Name("kept")))
+	dropped := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("dropped")))
+	kept.IncrBy(50)
+	kept.Abort(false)
+	dropped.Abort(true)
+	if count := p.BarCount(); count != 1 {
+		t.Errorf("BarCount want: %d, got: %d\n", 1, count)
+	}
+	p.Wait()
+
+	if out := buf.String(); !strings.Contains(out, "kept[") || !strings.Contains(out, "✗") {
+		t.Errorf("abort mark isn't rendered: %q", out)
+	}
+}
+
+func TestBarAbortQueued(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard))
+	a := p.AddBar(100)
+	b := p.AddBar(100)
+	q := p.AddBar(100, BarQueueAfter(a))
+	q.Abort(true)
+	if got := p.BarCount(); got != 2 {
+		t.Errorf("BarCount want 2, got %d", got)
+	}
+	a.IncrBy(100)
+	b.IncrBy(100)
+	p.Wait()
+	if err := p.Close(); err == nil || !strings.Contains(err.Error(), "bar id 02 aborted") {
+		t.Errorf("want queued bar reported as aborted, got %v", err)
+	}
+}
+
+func TestBarExtender(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend), WithWidth(10), WithManualRefresh(make(chan time.Time)))
+
+	bar := p.AddBar(100, BarTrim(), BarExtender(func(st *decor.Statistics) string {
+		return fmt.Sprintf("chunk %d\nof %d", st.Current/10, st.Total/10)
+	}))
+	bar.IncrBy(50)
+	bar.SetSubStatus("file.txt")
+	if got, want := bar.String(), "[===>----]\nchunk 5\nof 10\nfile.txt\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	bar.SetSubStatus("")
+	if got, want := bar.String(), "[===>----]\nchunk 5\nof 10\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	bar.SetSubStatus("very-long-file-name.txt")
+	if got, want := bar.String(), "[===>----]\nchunk 5\nof 10\nvery-long…\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	bar.IncrBy(50)
+	p.Wait()
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Add(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func TestBarClock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	p := New(WithOutput(ioutil.Discard), WithWidth(14), WithManualRefresh(make(chan time.Time)), WithClock(clock))
+
+	bar := p.AddBar(100, BarTrim(),
+		PrependDecorators(decor.Elapsed(decor.ET_STYLE_GO)),
+		AppendDecorators(decor.Any(func(st *decor.Statistics) string {
+			return fmt.Sprint(st.ETA)
+		})),
+	)
+	clock.Add(5 * time.Second)
+	bar.IncrBy(50)
+	if got, want := bar.String(), "5s[===>----]5s\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	bar.Pause()
+	clock.Add(time.Minute)
+	bar.Resume()
+	clock.Add(time.Second)
+	if got, want := bar.String(), "6s[===>----]6s\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	bar.IncrBy(50)
+	p.Wait()
+}
+
+func TestBarHidden(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend), WithWidth(10), WithManualRefresh(make(chan time.Time)))
+
+	a := p.AddBar(100, BarTrim())
+	b := p.AddBar(100, BarTrim(), BarHidden(), PrependDecorators(decor.Name("b")))
+	if got := p.BarCount(); got != 1 {
+		t.Errorf("BarCount want 1, got %d", got)
+	}
+	if got := b.String(); got != "" {
+		t.Errorf("hidden bar want %q, got %q", "", got)
+	}
+	b.Show()
+	if got := p.BarCount(); got != 2 {
+		t.Errorf("BarCount want 2, got %d", got)
+	}
+	b.Hide()
+	if got := p.BarCount(); got != 1 {
+		t.Errorf("BarCount want 1, got %d", got)
+	}
+	a.IncrBy(100)
+	b.IncrBy(100)
+	// hidden bar is shown by Wait, as it can't complete otherwise
+	p.Wait()
+
+	if !strings.HasSuffix(buf.String(), "b[=======]\n") {
+		t.Errorf("final frame of hidden bar is missing: %q", buf.String())
+	}
+}
@@ -1,6 +1,7 @@
 package mpb
 
 import (
+	"bytes"
 	"io/ioutil"
 	"testing"
 
@@ -41,3 +42,44 @@ func BenchmarkIncrSingleBarWithNameAndEwmaETADecorator(b *testing.B) {
 		bar.Increment()
 	}
 }
+
+func BenchmarkIncrSingleBarParallel(b *testing.B) {
+	p := New(WithOutput(ioutil.Discard))
+	bar := p.AddBar(int64(b.N))
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			bar.Increment()
+		}
+	})
+}
+
+func BenchmarkIncrSingleBarParallelSharded(b *testing.B) {
+	p := New(WithOutput(ioutil.Discard))
+	bar := p.AddBar(int64(b.N))
+	shards := make(chan *Shard, 64)
+	for _, shard := range bar.ShardedCounter(cap(shards)) {
+		shards <- shard
+	}
+	b.RunParallel(func(pb *testing.PB) {
+		shard := <-shards
+		for pb.Next() {
+			shard.Increment()
+		}
+	})
+}
+
+func BenchmarkDraw(b *testing.B) {
+	s := newTestState()
+	s.width = 80
+	s.total = 100
+	s.current = 42
+	s.pDecorators = []decor.Decorator{decor.Name("test")}
+	s.aDecorators = []decor.Decorator{decor.Percentage()}
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		buf.ReadFrom(s.draw(100))
+	}
+}
@@ -0,0 +1,99 @@
+package mpb
+
+import "io"
+
+type chunk struct {
+	start, size, current int64
+}
+
+// Chunks adds a bar of provided total, split into n equal range segments,
+// like byte ranges of a parallel download. Returned writers, one per chunk,
+// fill their own region of the bar, by amount of bytes written. Writes past
+// chunk's size aren't counted. The bar completes, once all chunks are done.
+func Chunks(p *Progress, total int64, n int, options ...BarOption) (*Bar, []io.Writer) {
+	if n < 1 || int64(n) > total {
+		n = 1
+	}
+	size := total / int64(n)
+	chunks := make([]chunk, n)
+	for i := range chunks {
+		chunks[i] = chunk{start: int64(i) * size, size: size}
+	}
+	// last chunk takes the remainder
+	chunks[n-1].size = total - chunks[n-1].start
+
+	options = append(options, barChunks(chunks))
+	bar := p.AddBar(total, options...)
+	if bar == nil {
+		return nil, nil
+	}
+
+	writers := make([]io.Writer, n)
+	for i := range writers {
+		writers[i] = &chunkWriter{bar: bar, index: i}
+	}
+	return bar, writers
+}
+
+func barChunks(chunks []chunk) BarOption {
+	return func(s *bState) {
+		s.chunks = chunks
+	}
+}
+
+type chunkWriter struct {
+	bar   *Bar
+	index int
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.bar.chunkIncrBy(w.index, int64(len(p)))
+	return len(p), nil
+}
+
+// chunkIncrBy increments chunk i and the bar by n, up to chunk's size.
+func (b *Bar) chunkIncrBy(i int, n int64) {
+	select {
+	case b.operateState <- func(s *bState) {
+		c := &s.chunks[i]
+		if c.current+n > c.size {
+			n = c.size - c.current
+		}
+		if n <= 0 {
+			return
+		}
+		c.current += n
+		s.incrBy(n)
+	}:
+	case <-b.done:
+	}
+}
+
+// fillChunks draws each cell as filled, if the point of range it stands for
+// has been written by its chunk.
+func (s *bState) fillChunks(barWidth int64) {
+	var k int
+	if s.reverse {
+		k = len(s.chunks) - 1
+	}
+	for i := int64(0); i < barWidth; i++ {
+		cell := i
+		if s.reverse {
+			// drawn from the right edge, so ranges go backwards
+			cell = barWidth - 1 - i
+		}
+		// middle of the range, the cell stands for
+		pos := (2*cell + 1) * s.total / (2 * barWidth)
+		for k < len(s.chunks)-1 && pos >= s.chunks[k].start+s.chunks[k].size {
+			k++
+		}
+		for k > 0 && pos < s.chunks[k].start {
+			k--
+		}
+		if pos < s.chunks[k].start+s.chunks[k].current {
+			s.bufB.WriteString(s.style.Fill)
+		} else {
+			s.bufB.WriteString(s.style.Empty)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package mpb
+
+import "time"
+
+// Clock is source of current time, which bars measure their elapsed time,
+// ETA and speed by. Provide a fake one, to make them deterministic in
+// tests, see WithClock and BarClock.
+type Clock interface {
+	Now() time.Time
+}
+
+// now is current time by the bar's clock, if set.
+func (s *bState) now() time.Time {
+	if s.clock == nil {
+		return time.Now()
+	}
+	return s.clock.Now()
+}
+
+// now is bState.now for use outside of the bar's goroutine, by proxy
+// reader and writer.
+func (b *Bar) now() time.Time {
+	if b.clock == nil {
+		return time.Now()
+	}
+	return b.clock.Now()
+}
@@ -0,0 +1,48 @@
+package mpb
+
+import (
+	"io"
+	"os"
+
+	isatty "github.com/mattn/go-isatty"
+	"github.com/vbauerster/mpb/cwriter"
+)
+
+// ColorMode defines whether bars may emit color escape sequences.
+type ColorMode int
+
+const (
+	// ColorAuto enables color, if output is a terminal, unless overridden
+	// by NO_COLOR, CLICOLOR, CLICOLOR_FORCE or FORCE_COLOR env vars.
+	// This is default.
+	ColorAuto ColorMode = iota
+	// ColorNever disables color regardless of environment.
+	ColorNever
+	// ColorAlways enables color regardless of environment.
+	ColorAlways
+)
+
+// resolveColor resolves mode against environment and output.
+// See https://no-color.org and https://bixense.com/clicolors
+func resolveColor(mode ColorMode, out io.Writer) bool {
+	switch mode {
+	case ColorNever:
+		return false
+	case ColorAlways:
+		return true
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if v := os.Getenv("FORCE_COLOR"); v != "" && v != "0" {
+		return true
+	}
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return true
+	}
+	if os.Getenv("CLICOLOR") == "0" {
+		return false
+	}
+	f, ok := out.(*os.File)
+	return ok && isatty.IsTerminal(f.Fd()) && cwriter.EnableVT(f)
+}
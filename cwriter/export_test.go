@@ -1,3 +1,16 @@
 package cwriter
 
 var ClearCursorAndLine = clearCursorAndLine
+
+var (
+	AltScreenEnter  = altScreenEnter
+	AltScreenLeave  = altScreenLeave
+	CursorHome      = cursorHome
+	ClearLineRest   = clearLineRest
+	ClearScreenRest = clearScreenRest
+)
+
+var (
+	ReflowRows    = reflowRows
+	DisplayWidths = displayWidths
+)
@@ -8,6 +8,7 @@ import (
 	"os"
 
 	isatty "github.com/mattn/go-isatty"
+	"github.com/vbauerster/mpb/internal"
 	"golang.org/x/crypto/ssh/terminal"
 )
 
@@ -20,6 +21,20 @@ var (
 	cursorUp           = fmt.Sprintf("%c[%dA", ESC, 1)
 	clearLine          = fmt.Sprintf("%c[2K\r", ESC)
 	clearCursorAndLine = cursorUp + clearLine
+	altScreenEnter     = fmt.Sprintf("%c[?1049h", ESC)
+	altScreenLeave     = fmt.Sprintf("%c[?1049l", ESC)
+	cursorHome         = fmt.Sprintf("%c[H", ESC)
+	clearLineRest      = fmt.Sprintf("%c[K", ESC)
+	clearScreenRest    = fmt.Sprintf("%c[J", ESC)
+	setTitle           = fmt.Sprintf("%c]0;%%s\a", ESC)
+)
+
+type vtMode int
+
+const (
+	vtUnknown vtMode = iota
+	vtOn
+	vtOff
 )
 
 // Writer is a buffered the writer that updates the terminal.
@@ -28,6 +43,24 @@ type Writer struct {
 	out       io.Writer
 	buf       bytes.Buffer
 	lineCount int
+	plain     bool
+	maxLines  int
+	alt       bool
+	altActive bool
+	header    string
+	above     bytes.Buffer
+	// title is pending window title, which is set on next flush, see
+	// SetTitle
+	title    string
+	titleSet bool
+	// vt is whether out understands escape sequences, detected once on
+	// windows, see EnableVT
+	vt vtMode
+	// termWidth and lineWidths are of the last flush, see reflowRows
+	termWidth  int
+	lineWidths []int
+	// teeBuf is reused by Tee
+	teeBuf []byte
 }
 
 // New returns a new Writer with defaults
@@ -35,9 +68,49 @@ func New(w io.Writer) *Writer {
 	return &Writer{out: w}
 }
 
+// NewPlain returns a new Writer, which never moves the cursor,
+// so each flush is appended to the output as is.
+func NewPlain(w io.Writer) *Writer {
+	return &Writer{out: w, plain: true}
+}
+
+// NewAltScreen returns a new Writer, which switches terminal to the
+// alternate screen on first flush and redraws each flush from the top
+// left corner, below provided header. Call Close to restore the screen.
+func NewAltScreen(w io.Writer, header string) *Writer {
+	return &Writer{out: w, alt: true, header: header}
+}
+
 // Flush flushes the underlying buffer
-func (w *Writer) Flush() error {
-	err := w.clearLines()
+func (w *Writer) Flush() (err error) {
+	if w.title != "" {
+		title := w.title
+		w.title = ""
+		if !w.plain && EnableVT(w.out) {
+			if _, err := fmt.Fprintf(w.out, setTitle, title); err != nil {
+				return err
+			}
+			w.titleSet = true
+		}
+	}
+	if w.alt {
+		return w.flushAlt()
+	}
+	if !w.plain {
+		tw, twErr := w.GetWidth()
+		if twErr == nil && tw < w.termWidth {
+			w.lineCount = reflowRows(w.lineWidths, tw)
+		}
+		err = w.clearLines()
+		w.capLines()
+		if twErr == nil {
+			w.termWidth = tw
+			w.lineWidths = displayWidths(w.lineWidths[:0], w.buf.Bytes())
+		}
+	}
+	if _, e := w.above.WriteTo(w.out); err == nil {
+		err = e
+	}
 	w.lineCount = bytes.Count(w.buf.Bytes(), []byte("\n"))
 	// WriteTo takes care of w.buf.Reset
 	if _, e := w.buf.WriteTo(w.out); err == nil {
@@ -46,6 +119,82 @@ func (w *Writer) Flush() error {
 	return err
 }
 
+// flushAlt redraws the alternate screen in place, clearing the rest of
+// each line and of the screen, so no leftovers of previous flush remain.
+func (w *Writer) flushAlt() error {
+	var out bytes.Buffer
+	if !w.altActive {
+		EnableVT(w.out)
+		out.WriteString(altScreenEnter)
+		w.altActive = true
+	}
+	w.capLines()
+	out.WriteString(cursorHome)
+	if w.header != "" {
+		out.WriteString(w.header + clearLineRest + "\n")
+	}
+	out.Write(bytes.Replace(w.buf.Bytes(), []byte("\n"), []byte(clearLineRest+"\n"), -1))
+	out.WriteString(clearScreenRest)
+	w.buf.Reset()
+	_, err := out.WriteTo(w.out)
+	return err
+}
+
+// Close clears window title, if it has been set, and restores original
+// screen contents, if the alternate screen has been entered.
+func (w *Writer) Close() error {
+	if w.titleSet {
+		w.titleSet = false
+		if _, err := fmt.Fprintf(w.out, setTitle, ""); err != nil {
+			return err
+		}
+	}
+	if !w.altActive {
+		return nil
+	}
+	w.altActive = false
+	if _, err := io.WriteString(w.out, altScreenLeave); err != nil {
+		return err
+	}
+	_, err := w.above.WriteTo(w.out)
+	return err
+}
+
+// Tee writes lines, written by WriteAbove and not flushed yet, to out, as
+// well as the underlying buffer, if frame is true. Escape sequences are
+// stripped, so out gets plain text. Must be called before Flush.
+func (w *Writer) Tee(out io.Writer, frame bool) error {
+	w.teeBuf = internal.StripEscapes(w.teeBuf[:0], w.above.Bytes())
+	if frame {
+		w.teeBuf = internal.StripEscapes(w.teeBuf, w.buf.Bytes())
+	}
+	if len(w.teeBuf) == 0 {
+		return nil
+	}
+	_, err := out.Write(w.teeBuf)
+	return err
+}
+
+// WriteAbove appends the contents of p to the buffer, which is flushed
+// above the lines of the underlying buffer and is never cleared. The
+// alternate screen can't keep such lines, so they are deferred until Close.
+func (w *Writer) WriteAbove(p []byte) (n int, err error) {
+	return w.above.Write(p)
+}
+
+// SetTitle sets terminal window title on next flush, by OSC escape
+// sequence. It's a no-op for the writer, returned by NewPlain, and for
+// windows console, which doesn't understand escape sequences.
+func (w *Writer) SetTitle(title string) {
+	w.title = title
+}
+
+// Reset discards the underlying buffer, so it isn't flushed. Lines
+// written by WriteAbove are kept.
+func (w *Writer) Reset() {
+	w.buf.Reset()
+}
+
 // Write appends the contents of p to the underlying buffer
 func (w *Writer) Write(p []byte) (n int, err error) {
 	return w.buf.Write(p)
@@ -61,6 +210,69 @@ func (w *Writer) ReadFrom(r io.Reader) (n int64, err error) {
 	return w.buf.ReadFrom(r)
 }
 
+// reflowRows returns number of terminal rows, lines of provided display
+// widths occupy, once terminal is shrunk to width tw and has reflowed
+// them, so all of them are cleared on the next flush.
+func reflowRows(lineWidths []int, tw int) (rows int) {
+	if tw <= 0 {
+		return len(lineWidths)
+	}
+	for _, lw := range lineWidths {
+		if lw <= tw {
+			rows++
+		} else {
+			rows += (lw + tw - 1) / tw
+		}
+	}
+	return rows
+}
+
+// displayWidths appends display width of each line of b to dst.
+func displayWidths(dst []int, b []byte) []int {
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			break
+		}
+		dst = append(dst, internal.DisplayWidth(string(b[:i])))
+		b = b[i+1:]
+	}
+	return dst
+}
+
+// SetMaxLines caps number of lines, flushed at once, to n. Lines, which
+// don't fit, are replaced by a summary line. Zero n means terminal height,
+// if output is a terminal. Cursor can't be moved up beyond the top of the
+// terminal, so overflowing lines would be duplicated on each flush.
+func (w *Writer) SetMaxLines(n int) {
+	w.maxLines = n
+}
+
+// capLines cuts the buffer down to max lines, including summary line.
+func (w *Writer) capLines() {
+	max := w.maxLines
+	if max == 0 {
+		if th, err := w.GetHeight(); err == nil {
+			// leave a row for the cursor
+			max = th - 1
+			if w.header != "" {
+				max--
+			}
+		}
+	}
+	b := w.buf.Bytes()
+	count := bytes.Count(b, []byte("\n"))
+	if max <= 0 || count <= max {
+		return
+	}
+	var cut int
+	for i := 0; i < max-1; i++ {
+		cut += bytes.IndexByte(b[cut:], '\n') + 1
+	}
+	w.buf.Truncate(cut)
+	fmt.Fprintf(&w.buf, "... %d more lines\n", count-(max-1))
+}
+
 func (w *Writer) GetWidth() (int, error) {
 	if f, ok := w.out.(*os.File); ok {
 		if isatty.IsTerminal(f.Fd()) {
@@ -70,3 +282,13 @@ func (w *Writer) GetWidth() (int, error) {
 	}
 	return -1, NotATTY
 }
+
+func (w *Writer) GetHeight() (int, error) {
+	if f, ok := w.out.(*os.File); ok {
+		if isatty.IsTerminal(f.Fd()) {
+			_, th, err := terminal.GetSize(int(f.Fd()))
+			return th, err
+		}
+	}
+	return -1, NotATTY
+}
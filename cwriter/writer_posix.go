@@ -7,6 +7,11 @@ import (
 	"strings"
 )
 
+// EnableVT is a no-op, escape sequences are always supported.
+func EnableVT(out io.Writer) bool {
+	return true
+}
+
 func (w *Writer) clearLines() error {
 	_, err := io.WriteString(w.out, strings.Repeat(clearCursorAndLine, w.lineCount))
 	return err
@@ -4,6 +4,7 @@ package cwriter_test
 
 import (
 	"bytes"
+	"strings"
 	"testing"
 
 	. "github.com/vbauerster/mpb/cwriter"
@@ -32,3 +33,58 @@ func TestWriterPosix(t *testing.T) {
 		})
 	}
 }
+
+func TestWriterMaxLines(t *testing.T) {
+	out := new(bytes.Buffer)
+	w := New(out)
+	w.SetMaxLines(3)
+
+	w.Write([]byte("1\n2\n3\n4\n5\n"))
+	w.Flush()
+	w.Write([]byte("1\n2\n"))
+	w.Flush()
+
+	// only capped lines are cleared on the next flush
+	want := "1\n2\n... 3 more lines\n" + strings.Repeat(ClearCursorAndLine, 3) + "1\n2\n"
+	if got := out.String(); got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
+
+func TestWriterAltScreen(t *testing.T) {
+	out := new(bytes.Buffer)
+	w := NewAltScreen(out, "header")
+
+	w.Write([]byte("foo\n"))
+	w.Flush()
+	w.Write([]byte("bar\n"))
+	w.Flush()
+	w.Close()
+
+	frame := func(s string) string {
+		return CursorHome + "header" + ClearLineRest + "\n" + s + ClearLineRest + "\n" + ClearScreenRest
+	}
+	want := AltScreenEnter + frame("foo") + frame("bar") + AltScreenLeave
+	if got := out.String(); got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
+
+func TestWriterAbove(t *testing.T) {
+	out := new(bytes.Buffer)
+	w := New(out)
+
+	w.Write([]byte("bar 1\n"))
+	w.Flush()
+	w.WriteAbove([]byte("log\n"))
+	w.Write([]byte("bar 2\n"))
+	w.Flush()
+	w.Write([]byte("bar 3\n"))
+	w.Flush()
+
+	// lines written above are never cleared
+	want := "bar 1\n" + ClearCursorAndLine + "log\nbar 2\n" + ClearCursorAndLine + "bar 3\n"
+	if got := out.String(); got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,28 @@
+package cwriter_test
+
+import (
+	"testing"
+
+	. "github.com/vbauerster/mpb/cwriter"
+)
+
+func TestReflowRows(t *testing.T) {
+	widths := DisplayWidths(nil, []byte("12345678\n1234\n\x1b[32m123456789012\x1b[0m\n"))
+	if len(widths) != 3 || widths[0] != 8 || widths[1] != 4 || widths[2] != 12 {
+		t.Fatalf("unexpected display widths %v", widths)
+	}
+
+	tests := []struct {
+		tw, want int
+	}{
+		{tw: 12, want: 3},
+		{tw: 8, want: 4},
+		{tw: 4, want: 6},
+		{tw: 0, want: 3},
+	}
+	for _, test := range tests {
+		if got := ReflowRows(widths, test.tw); got != test.want {
+			t.Errorf("tw %d: want %d rows, got %d", test.tw, test.want, got)
+		}
+	}
+}
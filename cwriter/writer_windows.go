@@ -18,8 +18,13 @@ var (
 	procSetConsoleCursorPosition   = kernel32.NewProc("SetConsoleCursorPosition")
 	procFillConsoleOutputCharacter = kernel32.NewProc("FillConsoleOutputCharacterW")
 	procFillConsoleOutputAttribute = kernel32.NewProc("FillConsoleOutputAttribute")
+	procGetConsoleMode             = kernel32.NewProc("GetConsoleMode")
+	procSetConsoleMode             = kernel32.NewProc("SetConsoleMode")
 )
 
+// enableVirtualTerminalProcessing console mode flag, available since Windows 10
+const enableVirtualTerminalProcessing = 0x4
+
 type (
 	short int16
 	word  uint16
@@ -50,13 +55,40 @@ type FdWriter interface {
 	Fd() uintptr
 }
 
+// EnableVT enables virtual terminal processing of console, out refers to,
+// so escape sequences are interpreted, as on other platforms. Reports
+// whether out understands escape sequences, which non console outputs,
+// like files or cygwin pipes, are assumed to do.
+func EnableVT(out io.Writer) bool {
+	f, ok := out.(FdWriter)
+	if !ok || !isatty.IsTerminal(f.Fd()) {
+		return true
+	}
+	var mode dword
+	if r, _, _ := procGetConsoleMode.Call(f.Fd(), uintptr(unsafe.Pointer(&mode))); r == 0 {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	r, _, _ := procSetConsoleMode.Call(f.Fd(), uintptr(mode|enableVirtualTerminalProcessing))
+	return r != 0
+}
+
+// clearLines moves cursor by escape sequences, if console supports them,
+// otherwise by console API, which is the only option on legacy consoles.
 func (w *Writer) clearLines() error {
-	f, ok := w.out.(FdWriter)
-	if ok && !isatty.IsTerminal(f.Fd()) {
+	if w.vt == vtUnknown {
+		w.vt = vtOff
+		if EnableVT(w.out) {
+			w.vt = vtOn
+		}
+	}
+	if w.vt == vtOn {
 		_, err := io.WriteString(w.out, strings.Repeat(clearCursorAndLine, w.lineCount))
 		return err
 	}
-	fd := f.Fd()
+	fd := w.out.(FdWriter).Fd()
 	var info consoleScreenBufferInfo
 	procGetConsoleScreenBufferInfo.Call(fd, uintptr(unsafe.Pointer(&info)))
 
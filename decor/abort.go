@@ -0,0 +1,27 @@
+package decor
+
+// OnAbort returns decorator, which wraps provided decorator, with sole
+// purpose to display provided message, once the bar is aborted. If message
+// is empty, abort reason is displayed, see Statistics.AbortReason.
+//
+//	`decorator` Decorator to wrap
+//
+//	`message` message to display on abort event
+func OnAbort(decorator Decorator, message string) Decorator {
+	return &onAbort{wrapper: wrapper{decorator}, msg: message}
+}
+
+type onAbort struct {
+	wrapper
+	msg string
+}
+
+func (d *onAbort) Decor(st *Statistics) string {
+	if st.AbortReason == nil {
+		return d.Decorator.Decor(st)
+	}
+	if d.msg == "" {
+		return d.formatMsg(st.AbortReason.Error())
+	}
+	return d.formatMsg(d.msg)
+}
@@ -0,0 +1,40 @@
+package decor
+
+// DecorFunc is a func, which renders decorator's message from Statistics.
+type DecorFunc func(*Statistics) string
+
+// Any adapts provided DecorFunc into a Decorator, with width config and
+// width sync support, so there is no need to implement Decorator by hand.
+//
+//	`fn` DecorFunc to call on each render cycle
+//
+//	`wcc` optional WC config
+func Any(fn DecorFunc, wcc ...WC) Decorator {
+	var wc WC
+	for _, widthConf := range wcc {
+		wc = widthConf
+	}
+	wc.Init()
+	d := &anyDecorator{
+		WC: wc,
+		fn: fn,
+	}
+	return d
+}
+
+type anyDecorator struct {
+	WC
+	fn          DecorFunc
+	completeMsg *string
+}
+
+func (d *anyDecorator) Decor(st *Statistics) string {
+	if st.Completed && d.completeMsg != nil {
+		return d.FormatMsg(*d.completeMsg)
+	}
+	return d.FormatMsg(d.fn(st))
+}
+
+func (d *anyDecorator) OnCompleteMessage(msg string) {
+	d.completeMsg = &msg
+}
@@ -0,0 +1,38 @@
+package decor
+
+import "fmt"
+
+// Attempts returns attempts count decorator.
+//
+//	`format` printf compatible verb for attempts count, like "try %d" or "try %d/5"
+//
+//	`wcc` optional WC config
+func Attempts(format string, wcc ...WC) Decorator {
+	var wc WC
+	for _, widthConf := range wcc {
+		wc = widthConf
+	}
+	wc.Init()
+	d := &attemptsDecorator{
+		WC:     wc,
+		format: format,
+	}
+	return d
+}
+
+type attemptsDecorator struct {
+	WC
+	format      string
+	completeMsg *string
+}
+
+func (d *attemptsDecorator) Decor(st *Statistics) string {
+	if st.Completed && d.completeMsg != nil {
+		return d.FormatMsg(*d.completeMsg)
+	}
+	return d.FormatMsg(fmt.Sprintf(d.format, st.Attempts))
+}
+
+func (d *attemptsDecorator) OnCompleteMessage(msg string) {
+	d.completeMsg = &msg
+}
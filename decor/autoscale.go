@@ -0,0 +1,151 @@
+package decor
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var (
+	scalesKiB = []scale{{1, "b"}, {KiB, "KiB"}, {MiB, "MiB"}, {GiB, "GiB"}, {TiB, "TiB"}}
+	scalesKB  = []scale{{1, "b"}, {KB, "kB"}, {MB, "MB"}, {GB, "GB"}, {TB, "TB"}}
+)
+
+type scale struct {
+	div  int64
+	unit string
+}
+
+// pickScale returns the largest scale, in which v displays as at least 1.0,
+// so 998KiB is shown as 1.0MiB rather than as 998KiB.
+func pickScale(scales []scale, v float64) scale {
+	sc := scales[0]
+	for _, s := range scales[1:] {
+		if v < float64(s.div)*0.95 {
+			break
+		}
+		sc = s
+	}
+	return sc
+}
+
+// scaled is a value with fixed scale, formatted like CounterKiB.
+type scaled struct {
+	value float64
+	scale
+	suffix string
+}
+
+func (s scaled) Format(st fmt.State, verb rune) {
+	prec, ok := st.Precision()
+
+	if verb == 'd' || !ok {
+		prec = 0
+	}
+	if verb == 'f' && !ok {
+		prec = 6
+	}
+	if verb == 's' {
+		prec = 1
+	}
+
+	var res string
+	if s.div == 1 {
+		res = strconv.FormatInt(int64(s.value), 10)
+	} else {
+		res = strconv.FormatFloat(s.value/float64(s.div), 'f', prec, 64)
+	}
+
+	if st.Flag(' ') {
+		res += " "
+	}
+	res += s.unit + s.suffix
+
+	if w, ok := st.Width(); ok {
+		if len(res) < w {
+			pad := strings.Repeat(" ", w-len(res))
+			if st.Flag(int('-')) {
+				res += pad
+			} else {
+				res = pad + res
+			}
+		}
+	}
+
+	io.WriteString(st, res)
+}
+
+// AutoScale decorator displays current, total and average speed, all in
+// the same unit, which is picked per render cycle by the larger of current
+// and total, so units never get mixed within one bar. Speed is
+// Statistics.AverageSpeed, i.e. measured since the bar has started.
+//
+//	`unit` one of [UnitKiB|UnitKB]
+//
+//	`format` printf compatible verbs for current, total and speed, like "%.1f / %.1f %.1f",
+//	use explicit argument indexes to omit some, like "%[1].1f / %[2].1f"
+//
+//	`wcc` optional WC config
+//
+// format example if UnitKiB is chosen:
+//
+//	"% .1f / % .1f % .1f" = "0.5 MiB / 1.0 MiB 0.1 MiB/s"
+func AutoScale(unit int, format string, wcc ...WC) Decorator {
+	var wc WC
+	for _, widthConf := range wcc {
+		wc = widthConf
+	}
+	wc.Init()
+	scales := scalesKiB
+	if unit == UnitKB {
+		scales = scalesKB
+	}
+	d := &autoScaleDecorator{
+		WC:     wc,
+		scales: scales,
+		format: format,
+	}
+	return d
+}
+
+type autoScaleDecorator struct {
+	WC
+	scales      []scale
+	format      string
+	msg         string
+	completeMsg *string
+}
+
+func (d *autoScaleDecorator) Decor(st *Statistics) string {
+	if st.Completed {
+		if d.completeMsg != nil {
+			return d.FormatMsg(*d.completeMsg)
+		}
+		return d.FormatMsg(d.msg)
+	}
+
+	current, total := float64(st.Current), float64(st.Total)
+	speed := st.AverageSpeed
+
+	max := total
+	if current > max {
+		max = current
+	}
+	sc := pickScale(d.scales, max)
+
+	msg := fmt.Sprintf(d.format,
+		scaled{current, sc, ""},
+		scaled{total, sc, ""},
+		scaled{speed, sc, "/s"},
+	)
+	if !st.Detached {
+		d.msg = msg
+	}
+
+	return d.FormatMsg(msg)
+}
+
+func (d *autoScaleDecorator) OnCompleteMessage(msg string) {
+	d.completeMsg = &msg
+}
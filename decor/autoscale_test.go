@@ -0,0 +1,52 @@
+package decor
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPickScale(t *testing.T) {
+	cases := map[string]struct {
+		scales []scale
+		value  float64
+		unit   string
+	}{
+		"0":       {scalesKiB, 0, "b"},
+		"1023":    {scalesKiB, 1023, "KiB"},
+		"998KiB":  {scalesKiB, 998 * KiB, "MiB"},
+		"900KiB":  {scalesKiB, 900 * KiB, "KiB"},
+		"3GiB":    {scalesKiB, 3 * GiB, "GiB"},
+		"999":     {scalesKB, 999, "kB"},
+		"800":     {scalesKB, 800, "b"},
+		"2000TB":  {scalesKB, 2000 * TB, "TB"},
+		"1500MiB": {scalesKiB, 1500 * MiB, "GiB"},
+	}
+	for name, tc := range cases {
+		if got := pickScale(tc.scales, tc.value).unit; got != tc.unit {
+			t.Errorf("%s: want %q, got %q\n", name, tc.unit, got)
+		}
+	}
+}
+
+func TestAutoScaleSameUnit(t *testing.T) {
+	d := AutoScale(UnitKiB, "% .1f / % .1f % .1f")
+	got := d.Decor(&Statistics{Current: 512 * KiB, Total: 2 * MiB, AverageSpeed: 256 * KiB})
+	if want := "0.5 MiB / 2.0 MiB 0.2 MiB/s"; got != want {
+		t.Errorf("want %q, got %q\n", want, got)
+	}
+}
+
+func TestScaledFormat(t *testing.T) {
+	s := scaled{1536, scale{KiB, "KiB"}, "/s"}
+	cases := map[string]string{
+		"%.1f":   "1.5KiB/s",
+		"% .1f":  "1.5 KiB/s",
+		"%10.1f": "  1.5KiB/s",
+		"%d":     "2KiB/s",
+	}
+	for verb, want := range cases {
+		if got := fmt.Sprintf(verb, s); got != want {
+			t.Errorf("%s: want %q, got %q\n", verb, want, got)
+		}
+	}
+}
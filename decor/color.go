@@ -0,0 +1,39 @@
+package decor
+
+// SGR sequences for common foreground colors, see Colorize
+// and mpb.BarStyleColor.
+const (
+	ColorRed     = "\x1b[31m"
+	ColorGreen   = "\x1b[32m"
+	ColorYellow  = "\x1b[33m"
+	ColorBlue    = "\x1b[34m"
+	ColorMagenta = "\x1b[35m"
+	ColorCyan    = "\x1b[36m"
+	ColorBold    = "\x1b[1m"
+
+	colorReset = "\x1b[0m"
+)
+
+// Colorize returns decorator, which wraps output of provided decorator
+// with sgr sequence, if color is enabled, see Statistics.Color. Escape
+// sequences aren't counted as width, so width sync isn't affected.
+//
+//	`decorator` Decorator to wrap
+//
+//	`sgr` SGR sequence, like ColorGreen
+func Colorize(decorator Decorator, sgr string) Decorator {
+	return &colorized{wrapper: wrapper{decorator}, sgr: sgr}
+}
+
+type colorized struct {
+	wrapper
+	sgr string
+}
+
+func (d *colorized) Decor(st *Statistics) string {
+	msg := d.Decorator.Decor(st)
+	if !st.Color || msg == "" {
+		return msg
+	}
+	return d.sgr + msg + colorReset
+}
@@ -195,7 +195,11 @@ func (d *countersDecorator) Decor(st *Statistics) string {
 	case UnitKB:
 		str = fmt.Sprintf(d.pairFormat, CounterKB(st.Current), CounterKB(st.Total))
 	default:
-		str = fmt.Sprintf(d.pairFormat, st.Current, st.Total)
+		if st.Locale != nil {
+			str = fmt.Sprintf(d.pairFormat, CounterGrouped{st.Current, *st.Locale}, CounterGrouped{st.Total, *st.Locale})
+		} else {
+			str = fmt.Sprintf(d.pairFormat, st.Current, st.Total)
+		}
 	}
 
 	return d.FormatMsg(str)
@@ -204,3 +208,63 @@ func (d *countersDecorator) Decor(st *Statistics) string {
 func (d *countersDecorator) OnCompleteMessage(msg string) {
 	d.completeMsg = &msg
 }
+
+// Remaining decorator displays amount left to do, with dynamic unit
+// measure adjustment.
+//
+//	`unit` one of [0|UnitKiB|UnitKB] zero for no unit
+//
+//	`format` printf compatible verb for remaining amount, like "%d left"
+//
+//	`wcc` optional WC config
+func Remaining(unit int, format string, wcc ...WC) Decorator {
+	var wc WC
+	for _, widthConf := range wcc {
+		wc = widthConf
+	}
+	wc.Init()
+	d := &remainingDecorator{
+		WC:     wc,
+		unit:   unit,
+		format: format,
+	}
+	return d
+}
+
+type remainingDecorator struct {
+	WC
+	unit        int
+	format      string
+	completeMsg *string
+}
+
+func (d *remainingDecorator) Decor(st *Statistics) string {
+	if st.Completed && d.completeMsg != nil {
+		return d.FormatMsg(*d.completeMsg)
+	}
+
+	remaining := st.Total - st.Current
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	var str string
+	switch d.unit {
+	case UnitKiB:
+		str = fmt.Sprintf(d.format, CounterKiB(remaining))
+	case UnitKB:
+		str = fmt.Sprintf(d.format, CounterKB(remaining))
+	default:
+		if st.Locale != nil {
+			str = fmt.Sprintf(d.format, CounterGrouped{remaining, *st.Locale})
+		} else {
+			str = fmt.Sprintf(d.format, remaining)
+		}
+	}
+
+	return d.FormatMsg(str)
+}
+
+func (d *remainingDecorator) OnCompleteMessage(msg string) {
+	d.completeMsg = &msg
+}
@@ -140,3 +140,38 @@ func TestCounterKB(t *testing.T) {
 		})
 	}
 }
+
+func TestCounterGrouped(t *testing.T) {
+	cases := map[string]struct {
+		value    int64
+		locale   Locale
+		verb     string
+		expected string
+	}{
+		"en %d":       {1234567, LocaleEnglish, "%d", "1,234,567"},
+		"en %d small": {999, LocaleEnglish, "%d", "999"},
+		"en %d neg":   {-1234, LocaleEnglish, "%d", "-1,234"},
+		"de %d":       {2000000, LocaleGerman, "%d", "2.000.000"},
+		"de %.1f":     {1234, LocaleGerman, "%.1f", "1.234,0"},
+		"ch %12d":     {1234567, LocaleSwiss, "%12d", "   1'234'567"},
+		"en %-8d":     {12345, LocaleEnglish, "%-8d", "12,345  "},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := fmt.Sprintf(tc.verb, CounterGrouped{tc.value, tc.locale})
+			if got != tc.expected {
+				t.Fatalf("expected: %q, got: %q\n", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestCountersLocale(t *testing.T) {
+	st := &Statistics{Current: 1234567, Total: 2000000, Locale: &LocaleEnglish}
+	if got := CountersNoUnit("%d / %d").Decor(st); got != "1,234,567 / 2,000,000" {
+		t.Errorf("unexpected counters: %q", got)
+	}
+	if got := Remaining(0, "%d left").Decor(st); got != "765,433 left" {
+		t.Errorf("unexpected remaining: %q", got)
+	}
+}
@@ -1,9 +1,10 @@
 package decor
 
 import (
-	"fmt"
+	"strings"
 	"time"
-	"unicode/utf8"
+
+	"github.com/vbauerster/mpb/internal"
 )
 
 const (
@@ -36,14 +37,85 @@ const (
 	ET_STYLE_HHMMSS
 	ET_STYLE_HHMM
 	ET_STYLE_MMSS
+	ET_STYLE_HUMAN
 )
 
-// Statistics is a struct, which gets passed to a Decorator.
+// Statistics is a struct, which gets passed to a Decorator. It is reused
+// across frames, so a Decorator must not keep a reference to it.
 type Statistics struct {
 	ID        int
 	Completed bool
 	Total     int64
 	Current   int64
+	// ETA is estimated remaining time, calculated by the bar itself.
+	ETA time.Duration
+	// Attempts is number of attempts, starting from 1.
+	// Incremented on each bar.Reset call.
+	Attempts int
+	// Finishing is true, while the bar is in finishing phase,
+	// i.e. it has reached its total, but isn't completed yet.
+	Finishing bool
+	// ReadOps is number of read calls, which returned data, made via bar's
+	// proxy reader.
+	ReadOps int64
+	// ReadBytes is number of bytes, read via bar's proxy reader.
+	ReadBytes int64
+	// SourceReadOps is number of read calls, made by bar's proxy reader on
+	// its source. It's greater than ReadOps, if the source has been read by
+	// chunks smaller than the buffer, see mpb.Bar.ProxyReaderSize.
+	SourceReadOps int64
+	// WriteOps is number of write calls, made via bar's proxy writer.
+	WriteOps int64
+	// WriteBytes is number of bytes, written via bar's proxy writer.
+	WriteBytes int64
+	// Refilled is part of Current, which has been done before the bar
+	// started, see mpb.Bar.SetRefillStyle. Speed and ETA decorators don't
+	// take it into account.
+	Refilled int64
+	// Locale is how numbers are grouped by counter decorators, nil if
+	// they aren't, see mpb.BarLocale.
+	Locale *Locale
+	// Color is true, if color output is enabled, see mpb.WithColorMode.
+	Color bool
+	// ETAError is mean absolute error of ETA, estimated by the bar while
+	// it was running. Available once the bar is complete.
+	ETAError time.Duration
+	// Succeeded, Failed and Aborted are counts of done bars, which are
+	// aggregated by aggregate bar. For other bars, Aborted is 1, once the
+	// bar is aborted or cancelled, and the rest are always zero.
+	Succeeded int
+	Failed    int
+	Aborted   int
+	// AbortReason is why the bar has been aborted, nil if it hasn't. See
+	// mpb.Bar.AbortWithReason, mpb.ErrAborted and mpb.ErrCancelled.
+	AbortReason error
+	// Paused is true, while the bar is paused, see mpb.Bar.Pause.
+	Paused bool
+	// PhaseDurations is time spent in each phase of the bar, see
+	// mpb.Bar.SetPhase. Nil, if the bar has no phases.
+	PhaseDurations map[string]time.Duration
+	// CurrentSpeed is rate of Current per second, over the last speed
+	// window, see mpb.BarSpeedWindow. AverageSpeed is the rate since the
+	// bar has started. Neither counts Refilled amount and paused time in.
+	CurrentSpeed float64
+	AverageSpeed float64
+	// SpeedSampled is true, once the speed window has enough samples to
+	// tell CurrentSpeed. Until then, CurrentSpeed is zero, like as if the
+	// bar has stalled.
+	SpeedSampled bool
+	// Detached is true, while the bar is drawn out of its render cycle, by
+	// mpb.Progress.Preview, mpb.Progress.RenderFrame or mpb.Bar.String.
+	// Decorators, which keep state across frames, must not update it then.
+	Detached bool
+}
+
+// Speed returns CurrentSpeed, or AverageSpeed, until the former is
+// sampled, see SpeedSampled.
+func (s *Statistics) Speed() float64 {
+	if s.SpeedSampled {
+		return s.CurrentSpeed
+	}
+	return s.AverageSpeed
 }
 
 // Decorator interface.
@@ -54,10 +126,14 @@ type Decorator interface {
 }
 
 // Syncable interface.
-// All decorators implement this interface implicitly.
+// All decorators implement this interface implicitly, by embedding WC.
 // Its Syncable method exposes width sync channel, if sync is enabled.
+// Its FinishSync method is called by mpb library after each Decor call, it
+// takes part in width sync on decorator's behalf, if FormatMsg hasn't been
+// called, so a decorator can never block other bars.
 type Syncable interface {
 	Syncable() (bool, chan int)
+	FinishSync(width int)
 }
 
 // OnCompleteMessenger interface.
@@ -80,6 +156,35 @@ type ShutdownListener interface {
 	Shutdown()
 }
 
+// PauseListener interface.
+// If decorator measures time, it should implement this interface,
+// in order to exclude time, the bar has been paused for.
+type PauseListener interface {
+	Pause()
+	Resume()
+}
+
+// Clock is source of current time, see ClockReceiver.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockReceiver interface.
+// If decorator measures time, it should implement this interface, in order
+// to tell time by the bar's clock, see mpb.BarClock.
+type ClockReceiver interface {
+	SetClock(Clock)
+}
+
+// Alignment of message within its width, see WC.Align.
+const (
+	// AlignDefault aligns to the right, unless DidentRight bit is set.
+	AlignDefault = iota
+	AlignLeft
+	AlignRight
+	AlignCenter
+)
+
 // Global convenience shortcuts
 var (
 	WCSyncWidth  = WC{C: DSyncWidth}
@@ -88,50 +193,114 @@ var (
 	WCSyncSpaceR = WC{C: DSyncSpaceR}
 )
 
-// WC is a struct with two public fields W and C, both of int type.
-// W represents width and C represents bit set of width related config.
+// WC is a struct with four public fields W, C, M and Align, all of int type.
+// W represents min width and C represents bit set of width related config.
+// M represents max width, if set, longer message is truncated with ellipsis.
+// With DSyncWidth bit set, W is min width and M effectively caps width of
+// the whole column. Align is one of AlignDefault, AlignLeft, AlignRight and
+// AlignCenter, it overrides DidentRight bit, unless it's AlignDefault.
 type WC struct {
-	W      int
-	C      int
-	format string
-	wsync  chan int
+	W     int
+	C     int
+	M     int
+	Align int
+	wsync *widthSync
+}
+
+// widthSync is shared by all copies of initialized WC.
+type widthSync struct {
+	ch     chan int
+	synced bool
+	max    int
 }
 
-// FormatMsg formats final message according to WC.W and WC.C.
+// FormatMsg formats final message according to WC.W, WC.C, WC.M and WC.Align.
 // Should be called by any Decorator implementation.
 func (wc WC) FormatMsg(msg string) string {
-	if (wc.C & DSyncWidth) != 0 {
-		wc.wsync <- utf8.RuneCountInString(msg)
-		max := <-wc.wsync
-		if max == 0 {
-			max = wc.W
+	if wc.M > 0 {
+		msg = truncate(msg, wc.M)
+	}
+	if wc.wsync == nil {
+		return wc.align(msg, wc.W)
+	}
+	if !wc.wsync.synced {
+		width := internal.DisplayWidth(msg)
+		if width < wc.W {
+			width = wc.W
 		}
-		if (wc.C & DextraSpace) != 0 {
-			max++
+		wc.wsync.ch <- width
+		wc.wsync.max = <-wc.wsync.ch
+		wc.wsync.synced = true
+	}
+	max := wc.wsync.max
+	if max < wc.W {
+		max = wc.W
+	}
+	if (wc.C & DextraSpace) != 0 {
+		max++
+	}
+	return wc.align(msg, max)
+}
+
+// align pads msg with spaces up to provided display width.
+func (wc WC) align(msg string, width int) string {
+	pad := width - internal.DisplayWidth(msg)
+	if pad <= 0 {
+		return msg
+	}
+	align := wc.Align
+	if align == AlignDefault {
+		align = AlignRight
+		if (wc.C & DidentRight) != 0 {
+			align = AlignLeft
 		}
-		return fmt.Sprintf(fmt.Sprintf(wc.format, max), msg)
 	}
-	return fmt.Sprintf(fmt.Sprintf(wc.format, wc.W), msg)
+	switch align {
+	case AlignLeft:
+		return msg + strings.Repeat(" ", pad)
+	case AlignCenter:
+		return strings.Repeat(" ", pad/2) + msg + strings.Repeat(" ", pad-pad/2)
+	}
+	return strings.Repeat(" ", pad) + msg
+}
+
+// truncate cuts msg down to max display width, ending it with ellipsis.
+func truncate(msg string, max int) string {
+	return internal.Truncate(msg, max, "…")
 }
 
 // Init initializes width related config.
 func (wc *WC) Init() {
-	wc.format = "%%"
-	if (wc.C & DidentRight) != 0 {
-		wc.format += "-"
-	}
-	wc.format += "%ds"
 	if (wc.C & DSyncWidth) != 0 {
-		wc.wsync = make(chan int)
+		wc.wsync = &widthSync{ch: make(chan int)}
 	}
 }
 
 func (wc *WC) Syncable() (bool, chan int) {
-	return (wc.C & DSyncWidth) != 0, wc.wsync
+	if wc.wsync == nil {
+		return false, nil
+	}
+	return true, wc.wsync.ch
+}
+
+// FinishSync takes part in width sync with provided width, unless FormatMsg
+// has already done it during current render cycle. Called by mpb library,
+// once per render cycle, there is no need to call it from a Decorator.
+func (wc *WC) FinishSync(width int) {
+	if wc.wsync == nil {
+		return
+	}
+	if !wc.wsync.synced {
+		wc.wsync.ch <- width
+		wc.wsync.max = <-wc.wsync.ch
+	}
+	wc.wsync.synced = false
 }
 
 // OnComplete returns decorator, which wraps provided decorator, with sole
-// purpose to display provided message on complete event.
+// purpose to display provided message on complete event. Decorators, which
+// don't implement OnCompleteMessenger, like user defined ones, are wrapped,
+// so it works for any decorator.
 //
 //	`decorator` Decorator to wrap
 //
@@ -139,6 +308,19 @@ func (wc *WC) Syncable() (bool, chan int) {
 func OnComplete(decorator Decorator, message string) Decorator {
 	if d, ok := decorator.(OnCompleteMessenger); ok {
 		d.OnCompleteMessage(message)
+		return decorator
+	}
+	return &onComplete{wrapper: wrapper{decorator}, msg: message}
+}
+
+type onComplete struct {
+	wrapper
+	msg string
+}
+
+func (d *onComplete) Decor(st *Statistics) string {
+	if st.Completed {
+		return d.formatMsg(d.msg)
 	}
-	return decorator
+	return d.Decorator.Decor(st)
 }
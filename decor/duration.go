@@ -0,0 +1,72 @@
+package decor
+
+import (
+	"fmt"
+	"time"
+)
+
+// DurationFormatter formats duration for ETA and Elapsed decorators.
+type DurationFormatter func(time.Duration) string
+
+// etStyles is indexed by style, see SetETStyle and NewETStyle.
+var etStyles = []DurationFormatter{
+	ET_STYLE_GO: func(d time.Duration) string {
+		return fmt.Sprint(time.Duration(d.Seconds()) * time.Second)
+	},
+	ET_STYLE_HHMMSS: func(d time.Duration) string {
+		hours, minutes, seconds := splitDuration(d)
+		return fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
+	},
+	ET_STYLE_HHMM: func(d time.Duration) string {
+		hours, minutes, _ := splitDuration(d)
+		return fmt.Sprintf("%02d:%02d", hours, minutes)
+	},
+	ET_STYLE_MMSS: func(d time.Duration) string {
+		_, minutes, seconds := splitDuration(d)
+		return fmt.Sprintf("%02d:%02d", minutes, seconds)
+	},
+	ET_STYLE_HUMAN: func(d time.Duration) string {
+		hours, minutes, seconds := splitDuration(d)
+		switch {
+		case hours > 0:
+			return fmt.Sprintf("%dh %dm", hours, minutes)
+		case minutes > 0:
+			return fmt.Sprintf("%dm %ds", minutes, seconds)
+		}
+		return fmt.Sprintf("%ds", seconds)
+	},
+}
+
+func splitDuration(d time.Duration) (hours, minutes, seconds int64) {
+	hours = int64((d / time.Hour) % 60)
+	minutes = int64((d / time.Minute) % 60)
+	seconds = int64((d / time.Second) % 60)
+	return
+}
+
+// SetETStyle overrides formatter of provided style package wide, so all
+// ETA and Elapsed decorators of that style render durations consistently.
+// Must be called before any decorator is used, like from init func.
+func SetETStyle(style int, fn DurationFormatter) {
+	if style < 0 || style >= len(etStyles) || fn == nil {
+		return
+	}
+	etStyles[style] = fn
+}
+
+// NewETStyle registers provided formatter as a new style, which can be
+// passed to any ETA or Elapsed decorator as its style. Must be called
+// before any decorator is used, like from init func.
+func NewETStyle(fn DurationFormatter) int {
+	etStyles = append(etStyles, fn)
+	return len(etStyles) - 1
+}
+
+// formatDuration formats d according to style,
+// unknown style is formatted as ET_STYLE_GO.
+func formatDuration(style int, d time.Duration) string {
+	if style < 0 || style >= len(etStyles) || etStyles[style] == nil {
+		style = ET_STYLE_GO
+	}
+	return etStyles[style](d)
+}
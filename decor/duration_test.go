@@ -0,0 +1,68 @@
+package decor
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	d := 2*time.Hour + 3*time.Minute + 4*time.Second
+	tests := []struct {
+		style int
+		d     time.Duration
+		want  string
+	}{
+		{ET_STYLE_GO, d, "2h3m4s"},
+		{ET_STYLE_HHMMSS, d, "02:03:04"},
+		{ET_STYLE_HHMM, d, "02:03"},
+		{ET_STYLE_MMSS, d, "03:04"},
+		{ET_STYLE_HUMAN, d, "2h 3m"},
+		{ET_STYLE_HUMAN, 3*time.Minute + 4*time.Second, "3m 4s"},
+		{ET_STYLE_HUMAN, 4 * time.Second, "4s"},
+		{-1, d, "2h3m4s"},
+	}
+
+	for _, test := range tests {
+		if got := formatDuration(test.style, test.d); got != test.want {
+			t.Errorf("style %d: want %q, got %q", test.style, test.want, got)
+		}
+	}
+}
+
+func TestNewETStyle(t *testing.T) {
+	style := NewETStyle(func(d time.Duration) string {
+		return fmt.Sprintf("%.0f min", d.Minutes())
+	})
+
+	got := Elapsed(style).Decor(new(Statistics))
+	if want := "0 min"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestSetETStyle(t *testing.T) {
+	orig := etStyles[ET_STYLE_MMSS]
+	defer SetETStyle(ET_STYLE_MMSS, orig)
+
+	SetETStyle(ET_STYLE_MMSS, func(time.Duration) string { return "soon" })
+	got := ETA(ET_STYLE_MMSS).Decor(&Statistics{ETA: time.Minute})
+	if want := "soon"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestClockPause(t *testing.T) {
+	c := newClock()
+	c.start = c.start.Add(-time.Second)
+	c.Pause()
+	paused := c.since()
+	time.Sleep(50 * time.Millisecond)
+	if got := c.since(); got != paused {
+		t.Errorf("paused clock isn't frozen: %s != %s", got, paused)
+	}
+	c.Resume()
+	if got := c.since(); got < paused || got > paused+40*time.Millisecond {
+		t.Errorf("resumed clock counts paused time: %s", got)
+	}
+}
@@ -1,13 +1,8 @@
 package decor
 
-import (
-	"fmt"
-	"time"
-)
-
 // Elapsed returns elapsed time decorator.
 //
-//	`style` one of [ET_STYLE_GO|ET_STYLE_HHMMSS|ET_STYLE_HHMM|ET_STYLE_MMSS]
+//	`style` one of [ET_STYLE_GO|ET_STYLE_HHMMSS|ET_STYLE_HHMM|ET_STYLE_MMSS|ET_STYLE_HUMAN], or one returned by NewETStyle
 //
 //	`wcc` optional WC config
 func Elapsed(style int, wcc ...WC) Decorator {
@@ -17,17 +12,17 @@ func Elapsed(style int, wcc ...WC) Decorator {
 	}
 	wc.Init()
 	d := &elapsedDecorator{
-		WC:        wc,
-		style:     style,
-		startTime: time.Now(),
+		WC:    wc,
+		style: style,
+		clock: newClock(),
 	}
 	return d
 }
 
 type elapsedDecorator struct {
 	WC
+	clock
 	style       int
-	startTime   time.Time
 	completeMsg *string
 }
 
@@ -36,24 +31,8 @@ func (d *elapsedDecorator) Decor(st *Statistics) string {
 		return d.FormatMsg(*d.completeMsg)
 	}
 
-	var str string
-	timeElapsed := time.Since(d.startTime)
-	hours := int64((timeElapsed / time.Hour) % 60)
-	minutes := int64((timeElapsed / time.Minute) % 60)
-	seconds := int64((timeElapsed / time.Second) % 60)
-
-	switch d.style {
-	case ET_STYLE_GO:
-		str = fmt.Sprint(time.Duration(timeElapsed.Seconds()) * time.Second)
-	case ET_STYLE_HHMMSS:
-		str = fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
-	case ET_STYLE_HHMM:
-		str = fmt.Sprintf("%02d:%02d", hours, minutes)
-	case ET_STYLE_MMSS:
-		str = fmt.Sprintf("%02d:%02d", minutes, seconds)
-	}
-
-	return d.FormatMsg(str)
+	timeElapsed := d.since()
+	return d.FormatMsg(formatDuration(d.style, timeElapsed))
 }
 
 func (d *elapsedDecorator) OnCompleteMessage(msg string) {
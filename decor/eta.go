@@ -13,7 +13,7 @@ type TimeNormalizer func(time.Duration) time.Duration
 
 // EwmaETA exponential-weighted-moving-average based ETA decorator.
 //
-//	`style` one of [ET_STYLE_GO|ET_STYLE_HHMMSS|ET_STYLE_HHMM|ET_STYLE_MMSS]
+//	`style` one of [ET_STYLE_GO|ET_STYLE_HHMMSS|ET_STYLE_HHMM|ET_STYLE_MMSS|ET_STYLE_HUMAN], or one returned by NewETStyle
 //
 //	`age` is the previous N samples to average over.
 //
@@ -24,7 +24,7 @@ func EwmaETA(style int, age float64, wcc ...WC) Decorator {
 
 // MovingAverageETA decorator relies on MovingAverage implementation to calculate its average.
 //
-//	`style` one of [ET_STYLE_GO|ET_STYLE_HHMMSS|ET_STYLE_HHMM|ET_STYLE_MMSS]
+//	`style` one of [ET_STYLE_GO|ET_STYLE_HHMMSS|ET_STYLE_HHMM|ET_STYLE_MMSS|ET_STYLE_HUMAN], or one returned by NewETStyle
 //
 //	`average` available implementations of MovingAverage [ewma.MovingAverage|NewMedian|NewMedianEwma]
 //
@@ -60,24 +60,12 @@ func (d *movingAverageETA) Decor(st *Statistics) string {
 	}
 
 	v := internal.Round(d.average.Value())
-	remaining := d.normalizer(time.Duration((st.Total - st.Current) * int64(v)))
-	hours := int64((remaining / time.Hour) % 60)
-	minutes := int64((remaining / time.Minute) % 60)
-	seconds := int64((remaining / time.Second) % 60)
-
-	var str string
-	switch d.style {
-	case ET_STYLE_GO:
-		str = fmt.Sprint(time.Duration(remaining.Seconds()) * time.Second)
-	case ET_STYLE_HHMMSS:
-		str = fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
-	case ET_STYLE_HHMM:
-		str = fmt.Sprintf("%02d:%02d", hours, minutes)
-	case ET_STYLE_MMSS:
-		str = fmt.Sprintf("%02d:%02d", minutes, seconds)
+	remaining := time.Duration((st.Total - st.Current) * int64(v))
+	if !st.Detached {
+		// normalizers keep track of time between calls
+		remaining = d.normalizer(remaining)
 	}
-
-	return d.FormatMsg(str)
+	return d.FormatMsg(formatDuration(d.style, remaining))
 }
 
 func (d *movingAverageETA) NextAmount(n int, wdd ...time.Duration) {
@@ -98,7 +86,7 @@ func (d *movingAverageETA) OnCompleteMessage(msg string) {
 
 // AverageETA decorator.
 //
-//	`style` one of [ET_STYLE_GO|ET_STYLE_HHMMSS|ET_STYLE_HHMM|ET_STYLE_MMSS]
+//	`style` one of [ET_STYLE_GO|ET_STYLE_HHMMSS|ET_STYLE_HHMM|ET_STYLE_MMSS|ET_STYLE_HUMAN], or one returned by NewETStyle
 //
 //	`wcc` optional WC config
 func AverageETA(style int, wcc ...WC) Decorator {
@@ -108,17 +96,17 @@ func AverageETA(style int, wcc ...WC) Decorator {
 	}
 	wc.Init()
 	d := &averageETA{
-		WC:        wc,
-		style:     style,
-		startTime: time.Now(),
+		WC:    wc,
+		style: style,
+		clock: newClock(),
 	}
 	return d
 }
 
 type averageETA struct {
 	WC
+	clock
 	style       int
-	startTime   time.Time
 	completeMsg *string
 }
 
@@ -127,32 +115,55 @@ func (d *averageETA) Decor(st *Statistics) string {
 		return d.FormatMsg(*d.completeMsg)
 	}
 
-	var str string
-	timeElapsed := time.Since(d.startTime)
-	v := internal.Round(float64(timeElapsed) / float64(st.Current))
+	timeElapsed := d.since()
+	v := internal.Round(float64(timeElapsed) / float64(st.Current-st.Refilled))
 	if math.IsInf(v, 0) || math.IsNaN(v) {
 		v = 0
 	}
 	remaining := time.Duration((st.Total - st.Current) * int64(v))
-	hours := int64((remaining / time.Hour) % 60)
-	minutes := int64((remaining / time.Minute) % 60)
-	seconds := int64((remaining / time.Second) % 60)
+	return d.FormatMsg(formatDuration(d.style, remaining))
+}
+
+func (d *averageETA) OnCompleteMessage(msg string) {
+	d.completeMsg = &msg
+}
 
-	switch d.style {
-	case ET_STYLE_GO:
-		str = fmt.Sprint(time.Duration(remaining.Seconds()) * time.Second)
-	case ET_STYLE_HHMMSS:
-		str = fmt.Sprintf("%02d:%02d:%02d", hours, minutes, seconds)
-	case ET_STYLE_HHMM:
-		str = fmt.Sprintf("%02d:%02d", hours, minutes)
-	case ET_STYLE_MMSS:
-		str = fmt.Sprintf("%02d:%02d", minutes, seconds)
+// ETA decorator, displays ETA estimated by the bar itself.
+// Unlike other ETA decorators, it doesn't need work duration measurement,
+// and it is the only one, which makes sense for aggregate bar.
+//
+//	`style` one of [ET_STYLE_GO|ET_STYLE_HHMMSS|ET_STYLE_HHMM|ET_STYLE_MMSS|ET_STYLE_HUMAN], or one returned by NewETStyle
+//
+//	`wcc` optional WC config
+func ETA(style int, wcc ...WC) Decorator {
+	var wc WC
+	for _, widthConf := range wcc {
+		wc = widthConf
+	}
+	wc.Init()
+	d := &etaDecorator{
+		WC:    wc,
+		style: style,
 	}
+	return d
+}
 
-	return d.FormatMsg(str)
+type etaDecorator struct {
+	WC
+	style       int
+	completeMsg *string
 }
 
-func (d *averageETA) OnCompleteMessage(msg string) {
+func (d *etaDecorator) Decor(st *Statistics) string {
+	if st.Completed && d.completeMsg != nil {
+		return d.FormatMsg(*d.completeMsg)
+	}
+
+	remaining := st.ETA
+	return d.FormatMsg(formatDuration(d.style, remaining))
+}
+
+func (d *etaDecorator) OnCompleteMessage(msg string) {
 	d.completeMsg = &msg
 }
 
@@ -197,3 +208,35 @@ func NopNormalizer() TimeNormalizer {
 		return remaining
 	}
 }
+
+// ETAAccuracy decorator displays mean absolute error of ETA, estimated by
+// the bar while it was running. Displays nothing, until the bar is complete.
+// Useful for tuning ETA estimation.
+//
+//	`format` printf compatible verb for error, like "eta error ±%s"
+//
+//	`wcc` optional WC config
+func ETAAccuracy(format string, wcc ...WC) Decorator {
+	var wc WC
+	for _, widthConf := range wcc {
+		wc = widthConf
+	}
+	wc.Init()
+	d := &etaAccuracyDecorator{
+		WC:     wc,
+		format: format,
+	}
+	return d
+}
+
+type etaAccuracyDecorator struct {
+	WC
+	format string
+}
+
+func (d *etaAccuracyDecorator) Decor(st *Statistics) string {
+	if !st.Completed && st.ETAError == 0 {
+		return d.FormatMsg("")
+	}
+	return d.FormatMsg(fmt.Sprintf(d.format, st.ETAError/time.Second*time.Second))
+}
@@ -0,0 +1,39 @@
+package decor
+
+import "fmt"
+
+// FailCount returns failed bars count decorator, makes sense for
+// aggregate bar only.
+//
+//	`format` printf compatible verb for failed count, like "%d failed"
+//
+//	`wcc` optional WC config
+func FailCount(format string, wcc ...WC) Decorator {
+	var wc WC
+	for _, widthConf := range wcc {
+		wc = widthConf
+	}
+	wc.Init()
+	d := &failCountDecorator{
+		WC:     wc,
+		format: format,
+	}
+	return d
+}
+
+type failCountDecorator struct {
+	WC
+	format      string
+	completeMsg *string
+}
+
+func (d *failCountDecorator) Decor(st *Statistics) string {
+	if st.Completed && d.completeMsg != nil {
+		return d.FormatMsg(*d.completeMsg)
+	}
+	return d.FormatMsg(fmt.Sprintf(d.format, st.Failed))
+}
+
+func (d *failCountDecorator) OnCompleteMessage(msg string) {
+	d.completeMsg = &msg
+}
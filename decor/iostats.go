@@ -0,0 +1,64 @@
+package decor
+
+import "fmt"
+
+// IOStats decorator displays number of io ops and average op size, made
+// via bar's proxy reader and writer, with dynamic unit measure adjustment.
+//
+//	`unit` one of [0|UnitKiB|UnitKB] zero for no unit
+//
+//	`pairFormat` printf compatible verbs for ops count and average op size, like "%d ops, avg %.1f"
+//
+//	`wcc` optional WC config
+//
+// pairFormat example if UnitKiB is chosen:
+//
+//	"%d ops, avg % .1f" = "42 ops, avg 32.0 KiB"
+func IOStats(unit int, pairFormat string, wcc ...WC) Decorator {
+	var wc WC
+	for _, widthConf := range wcc {
+		wc = widthConf
+	}
+	wc.Init()
+	d := &ioStatsDecorator{
+		WC:         wc,
+		unit:       unit,
+		pairFormat: pairFormat,
+	}
+	return d
+}
+
+type ioStatsDecorator struct {
+	WC
+	unit        int
+	pairFormat  string
+	completeMsg *string
+}
+
+func (d *ioStatsDecorator) Decor(st *Statistics) string {
+	if st.Completed && d.completeMsg != nil {
+		return d.FormatMsg(*d.completeMsg)
+	}
+
+	var avg int64
+	ops := st.ReadOps + st.WriteOps
+	if ops > 0 {
+		avg = (st.ReadBytes + st.WriteBytes) / ops
+	}
+
+	var str string
+	switch d.unit {
+	case UnitKiB:
+		str = fmt.Sprintf(d.pairFormat, ops, CounterKiB(avg))
+	case UnitKB:
+		str = fmt.Sprintf(d.pairFormat, ops, CounterKB(avg))
+	default:
+		str = fmt.Sprintf(d.pairFormat, ops, avg)
+	}
+
+	return d.FormatMsg(str)
+}
+
+func (d *ioStatsDecorator) OnCompleteMessage(msg string) {
+	d.completeMsg = &msg
+}
@@ -0,0 +1,76 @@
+package decor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Locale defines how numbers are grouped by counter decorators, see
+// mpb.BarLocale. Thousands separates groups of three digits, Decimal
+// separates fraction, if verb has precision, like "%.1f".
+type Locale struct {
+	Thousands string
+	Decimal   string
+}
+
+// Predefined locales
+var (
+	LocaleEnglish = Locale{Thousands: ",", Decimal: "."}
+	LocaleGerman  = Locale{Thousands: ".", Decimal: ","}
+	LocaleFrench  = Locale{Thousands: " ", Decimal: ","}
+	LocaleSwiss   = Locale{Thousands: "'", Decimal: "."}
+)
+
+// CounterGrouped is a number, which is formatted with thousands separator
+// of its locale, for any verb. Width and '-' flag are respected.
+type CounterGrouped struct {
+	N      int64
+	Locale Locale
+}
+
+func (c CounterGrouped) Format(st fmt.State, verb rune) {
+	var res string
+	if prec, ok := st.Precision(); ok && verb != 'd' {
+		res = strconv.FormatFloat(float64(c.N), 'f', prec, 64)
+	} else {
+		res = strconv.FormatInt(c.N, 10)
+	}
+	res = c.Locale.group(res)
+
+	if w, ok := st.Width(); ok {
+		if n := len([]rune(res)); n < w {
+			pad := strings.Repeat(" ", w-n)
+			if st.Flag(int('-')) {
+				res += pad
+			} else {
+				res = pad + res
+			}
+		}
+	}
+
+	io.WriteString(st, res)
+}
+
+// group inserts separators into decimal number, as formatted by strconv.
+func (l Locale) group(num string) string {
+	var sign, frac string
+	if strings.HasPrefix(num, "-") {
+		sign, num = "-", num[1:]
+	}
+	if i := strings.IndexByte(num, '.'); i >= 0 {
+		num, frac = num[:i], l.Decimal+num[i+1:]
+	}
+	var buf bytes.Buffer
+	buf.WriteString(sign)
+	for i, r := range num {
+		if i > 0 && (len(num)-i)%3 == 0 {
+			buf.WriteString(l.Thousands)
+		}
+		buf.WriteRune(r)
+	}
+	buf.WriteString(frac)
+	return buf.String()
+}
@@ -0,0 +1,75 @@
+package decor
+
+import "time"
+
+// clock measures time since start, excluding paused periods.
+// Embedded by time measuring decorators, so they implement PauseListener
+// and ClockReceiver.
+type clock struct {
+	src      Clock
+	start    time.Time
+	pausedAt time.Time
+}
+
+func newClock() clock {
+	return clock{start: time.Now()}
+}
+
+func (c *clock) now() time.Time {
+	if c.src == nil {
+		return time.Now()
+	}
+	return c.src.Now()
+}
+
+func (c *clock) since() time.Duration {
+	if !c.pausedAt.IsZero() {
+		return c.pausedAt.Sub(c.start)
+	}
+	return c.now().Sub(c.start)
+}
+
+// SetClock makes the clock to tell time by provided one, starting over.
+func (c *clock) SetClock(src Clock) {
+	c.src = src
+	c.start = c.now()
+	c.pausedAt = time.Time{}
+}
+
+// Pause stops the clock.
+func (c *clock) Pause() {
+	if c.pausedAt.IsZero() {
+		c.pausedAt = c.now()
+	}
+}
+
+// Resume restarts the clock, as if it has never been paused.
+func (c *clock) Resume() {
+	if c.pausedAt.IsZero() {
+		return
+	}
+	c.start = c.start.Add(c.now().Sub(c.pausedAt))
+	c.pausedAt = time.Time{}
+}
+
+// OnPause returns decorator, which wraps provided decorator, with sole
+// purpose to display provided message, while the bar is paused.
+//
+//	`decorator` Decorator to wrap
+//
+//	`message` message to display, while paused
+func OnPause(decorator Decorator, message string) Decorator {
+	return &onPause{wrapper: wrapper{decorator}, msg: message}
+}
+
+type onPause struct {
+	wrapper
+	msg string
+}
+
+func (d *onPause) Decor(st *Statistics) string {
+	if st.Paused {
+		return d.msg
+	}
+	return d.Decorator.Decor(st)
+}
@@ -179,16 +179,20 @@ func (d *movingAverageSpeed) Decor(st *Statistics) string {
 	}
 
 	speed := d.average.Value()
+	var msg string
 	switch d.unit {
 	case UnitKiB:
-		d.msg = fmt.Sprintf(d.unitFormat, SpeedKiB(speed))
+		msg = fmt.Sprintf(d.unitFormat, SpeedKiB(speed))
 	case UnitKB:
-		d.msg = fmt.Sprintf(d.unitFormat, SpeedKB(speed))
+		msg = fmt.Sprintf(d.unitFormat, SpeedKB(speed))
 	default:
-		d.msg = fmt.Sprintf(d.unitFormat, speed)
+		msg = fmt.Sprintf(d.unitFormat, speed)
+	}
+	if !st.Detached {
+		d.msg = msg
 	}
 
-	return d.FormatMsg(d.msg)
+	return d.FormatMsg(msg)
 }
 
 func (s *movingAverageSpeed) NextAmount(n int, wdd ...time.Duration) {
@@ -228,16 +232,16 @@ func AverageSpeed(unit int, unitFormat string, wcc ...WC) Decorator {
 		WC:         wc,
 		unit:       unit,
 		unitFormat: unitFormat,
-		startTime:  time.Now(),
+		clock:      newClock(),
 	}
 	return d
 }
 
 type averageSpeed struct {
 	WC
+	clock
 	unit        int
 	unitFormat  string
-	startTime   time.Time
 	msg         string
 	completeMsg *string
 }
@@ -250,21 +254,160 @@ func (d *averageSpeed) Decor(st *Statistics) string {
 		return d.FormatMsg(d.msg)
 	}
 
-	timeElapsed := time.Since(d.startTime)
-	speed := float64(st.Current) / timeElapsed.Seconds()
+	timeElapsed := d.since()
+	speed := float64(st.Current-st.Refilled) / timeElapsed.Seconds()
 
+	var msg string
 	switch d.unit {
 	case UnitKiB:
-		d.msg = fmt.Sprintf(d.unitFormat, SpeedKiB(speed))
+		msg = fmt.Sprintf(d.unitFormat, SpeedKiB(speed))
 	case UnitKB:
-		d.msg = fmt.Sprintf(d.unitFormat, SpeedKB(speed))
+		msg = fmt.Sprintf(d.unitFormat, SpeedKB(speed))
 	default:
-		d.msg = fmt.Sprintf(d.unitFormat, speed)
+		msg = fmt.Sprintf(d.unitFormat, speed)
+	}
+	if !st.Detached {
+		d.msg = msg
 	}
 
-	return d.FormatMsg(d.msg)
+	return d.FormatMsg(msg)
 }
 
 func (d *averageSpeed) OnCompleteMessage(msg string) {
 	d.completeMsg = &msg
 }
+
+// IOCounter provides total number of bytes transferred so far,
+// *mpb.Progress implements it.
+type IOCounter interface {
+	IOBytes() int64
+}
+
+// TotalSpeed decorator displays combined throughput of provided counter,
+// like all proxy driven bars of a container, with dynamic unit measure
+// adjustment. Throughput is ewma averaged between render cycles, time the
+// bar has been paused for isn't counted.
+//
+//	`counter` IOCounter, usually *mpb.Progress
+//
+//	`unit` one of [0|UnitKiB|UnitKB] zero for no unit
+//
+//	`unitFormat` printf compatible verb for value, like "%f" or "%d"
+//
+//	`wcc` optional WC config
+func TotalSpeed(counter IOCounter, unit int, unitFormat string, wcc ...WC) Decorator {
+	var wc WC
+	for _, widthConf := range wcc {
+		wc = widthConf
+	}
+	wc.Init()
+	d := &totalSpeed{
+		WC:         wc,
+		counter:    counter,
+		unit:       unit,
+		unitFormat: unitFormat,
+		average:    ewma.NewMovingAverage(),
+		clock:      newClock(),
+	}
+	return d
+}
+
+type totalSpeed struct {
+	WC
+	clock
+	counter     IOCounter
+	unit        int
+	unitFormat  string
+	average     ewma.MovingAverage
+	lastCall    time.Duration
+	lastBytes   int64
+	completeMsg *string
+}
+
+func (d *totalSpeed) Decor(st *Statistics) string {
+	if st.Completed && d.completeMsg != nil {
+		return d.FormatMsg(*d.completeMsg)
+	}
+
+	if !st.Detached {
+		now := d.since()
+		bytes := d.counter.IOBytes()
+		if elapsed := (now - d.lastCall).Seconds(); elapsed > 0 {
+			d.average.Add(float64(bytes-d.lastBytes) / elapsed)
+		}
+		d.lastCall, d.lastBytes = now, bytes
+	}
+	speed := d.average.Value()
+
+	var str string
+	switch d.unit {
+	case UnitKiB:
+		str = fmt.Sprintf(d.unitFormat, SpeedKiB(speed))
+	case UnitKB:
+		str = fmt.Sprintf(d.unitFormat, SpeedKB(speed))
+	default:
+		str = fmt.Sprintf(d.unitFormat, speed)
+	}
+
+	return d.FormatMsg(str)
+}
+
+func (d *totalSpeed) OnCompleteMessage(msg string) {
+	d.completeMsg = &msg
+}
+
+// Speed decorator displays speed of the bar, see Statistics.Speed. Unlike
+// other speed decorators, it keeps no state, speed is measured by the bar
+// over its speed window, see mpb.BarSpeedWindow. On complete, average
+// speed is displayed.
+//
+//	`unit` one of [0|UnitKiB|UnitKB] zero for no unit
+//
+//	`unitFormat` printf compatible verb for value, like "%f" or "%d"
+//
+//	`wcc` optional WC config
+func Speed(unit int, unitFormat string, wcc ...WC) Decorator {
+	var wc WC
+	for _, widthConf := range wcc {
+		wc = widthConf
+	}
+	wc.Init()
+	d := &speedDecorator{
+		WC:         wc,
+		unit:       unit,
+		unitFormat: unitFormat,
+	}
+	return d
+}
+
+type speedDecorator struct {
+	WC
+	unit        int
+	unitFormat  string
+	completeMsg *string
+}
+
+func (d *speedDecorator) Decor(st *Statistics) string {
+	speed := st.Speed()
+	if st.Completed {
+		if d.completeMsg != nil {
+			return d.FormatMsg(*d.completeMsg)
+		}
+		speed = st.AverageSpeed
+	}
+
+	var msg string
+	switch d.unit {
+	case UnitKiB:
+		msg = fmt.Sprintf(d.unitFormat, SpeedKiB(speed))
+	case UnitKB:
+		msg = fmt.Sprintf(d.unitFormat, SpeedKB(speed))
+	default:
+		msg = fmt.Sprintf(d.unitFormat, speed)
+	}
+	return d.FormatMsg(msg)
+}
+
+func (d *speedDecorator) OnCompleteMessage(msg string) {
+	d.completeMsg = &msg
+}
@@ -3,6 +3,7 @@ package decor
 import (
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestSpeedKiB(t *testing.T) {
@@ -140,3 +141,64 @@ func TestSpeedKB(t *testing.T) {
 		})
 	}
 }
+
+func TestSpeedDecorator(t *testing.T) {
+	cases := map[string]struct {
+		stat *Statistics
+		want string
+	}{
+		"current":  {&Statistics{CurrentSpeed: 2 * KiB, AverageSpeed: KiB, SpeedSampled: true}, "2.0KiB/s"},
+		"stalled":  {&Statistics{AverageSpeed: KiB, SpeedSampled: true}, "0b/s"},
+		"average":  {&Statistics{AverageSpeed: KiB}, "1.0KiB/s"},
+		"complete": {&Statistics{Completed: true, CurrentSpeed: 2 * KiB, AverageSpeed: KiB, SpeedSampled: true}, "1.0KiB/s"},
+	}
+	d := Speed(UnitKiB, "%.1f")
+	for name, tc := range cases {
+		if got := d.Decor(tc.stat); got != tc.want {
+			t.Errorf("%s: want: %q, got: %q\n", name, tc.want, got)
+		}
+	}
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+func TestAverageSpeedDetached(t *testing.T) {
+	start := time.Unix(0, 0)
+	d := AverageSpeed(0, "%.0f").(*averageSpeed)
+	d.SetClock(fixedClock(start))
+	d.src = fixedClock(start.Add(time.Second))
+
+	if got, want := d.Decor(&Statistics{Current: 10}), "10"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if got, want := d.Decor(&Statistics{Current: 50, Detached: true}), "50"; got != want {
+		t.Errorf("detached: want %q, got %q", want, got)
+	}
+	// completed bar shows speed of the last attached frame
+	if got, want := d.Decor(&Statistics{Completed: true}), "10"; got != want {
+		t.Errorf("completed: want %q, got %q", want, got)
+	}
+}
+
+type stepClock struct{ now time.Time }
+
+func (c *stepClock) Now() time.Time { return c.now }
+
+type ioCounter int64
+
+func (c *ioCounter) IOBytes() int64 { return int64(*c) }
+
+func TestTotalSpeedClock(t *testing.T) {
+	clock := &stepClock{time.Unix(0, 0)}
+	var counter ioCounter
+	d := TotalSpeed(&counter, UnitKiB, "%.1f")
+	d.(ClockReceiver).SetClock(clock)
+
+	clock.now = clock.now.Add(time.Second)
+	counter = 2 * KiB
+	if got, want := d.Decor(&Statistics{}), "2.0KiB/s"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
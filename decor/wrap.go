@@ -0,0 +1,54 @@
+package decor
+
+import "time"
+
+// wrapper embeds wrapped decorator and forwards optional interfaces to it,
+// so wrapping a decorator doesn't hide them from mpb library.
+type wrapper struct {
+	Decorator
+}
+
+// formatMsg formats msg by wrapped decorator's WC, if it has one, so
+// replacement message is aligned and synced the same way.
+func (d wrapper) formatMsg(msg string) string {
+	if f, ok := d.Decorator.(interface{ FormatMsg(string) string }); ok {
+		return f.FormatMsg(msg)
+	}
+	return msg
+}
+
+func (d wrapper) OnCompleteMessage(msg string) {
+	if m, ok := d.Decorator.(OnCompleteMessenger); ok {
+		m.OnCompleteMessage(msg)
+	}
+}
+
+func (d wrapper) NextAmount(n int, wdd ...time.Duration) {
+	if r, ok := d.Decorator.(AmountReceiver); ok {
+		r.NextAmount(n, wdd...)
+	}
+}
+
+func (d wrapper) Shutdown() {
+	if l, ok := d.Decorator.(ShutdownListener); ok {
+		l.Shutdown()
+	}
+}
+
+func (d wrapper) Pause() {
+	if l, ok := d.Decorator.(PauseListener); ok {
+		l.Pause()
+	}
+}
+
+func (d wrapper) Resume() {
+	if l, ok := d.Decorator.(PauseListener); ok {
+		l.Resume()
+	}
+}
+
+func (d wrapper) SetClock(c Clock) {
+	if r, ok := d.Decorator.(ClockReceiver); ok {
+		r.SetClock(c)
+	}
+}
@@ -1,6 +1,8 @@
 package mpb_test
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 	"testing"
 
@@ -29,6 +31,30 @@ func TestNameDecorator(t *testing.T) {
 			decorator: decor.Name("Test", decor.WC{W: 10, C: decor.DidentRight}),
 			want:      "Test      ",
 		},
+		{
+			decorator: decor.Name("Test", decor.WC{M: 4}),
+			want:      "Test",
+		},
+		{
+			decorator: decor.Name("Testing", decor.WC{M: 4}),
+			want:      "Tes…",
+		},
+		{
+			decorator: decor.Name("Testing", decor.WC{W: 6, M: 4}),
+			want:      "  Tes…",
+		},
+		{
+			decorator: decor.Name("Test", decor.WC{W: 9, Align: decor.AlignCenter}),
+			want:      "  Test   ",
+		},
+		{
+			decorator: decor.Name("Test", decor.WC{W: 6, C: decor.DidentRight, Align: decor.AlignRight}),
+			want:      "  Test",
+		},
+		{
+			decorator: decor.Name("Test", decor.WC{W: 6, Align: decor.AlignLeft}),
+			want:      "Test  ",
+		},
 	}
 
 	for _, test := range tests {
@@ -39,6 +65,136 @@ func TestNameDecorator(t *testing.T) {
 	}
 }
 
+func TestAnyDecorator(t *testing.T) {
+	fn := func(st *decor.Statistics) string {
+		return fmt.Sprintf("%d of %d", st.Current, st.Total)
+	}
+	tests := []struct {
+		decorator decor.Decorator
+		want      string
+	}{
+		{
+			decorator: decor.Any(fn),
+			want:      "3 of 10",
+		},
+		{
+			decorator: decor.Any(fn, decor.WC{W: 10}),
+			want:      "   3 of 10",
+		},
+		{
+			decorator: decor.OnComplete(decor.Any(fn), "done"),
+			want:      "3 of 10",
+		},
+	}
+
+	for _, test := range tests {
+		got := test.decorator.Decor(&decor.Statistics{Current: 3, Total: 10})
+		if got != test.want {
+			t.Errorf("Want: %q, Got: %q\n", test.want, got)
+		}
+	}
+}
+
+func TestOnCompleteOnAbortDecorator(t *testing.T) {
+	aborted := &decor.Statistics{Completed: true, AbortReason: errors.New("timeout")}
+	tests := []struct {
+		decorator decor.Decorator
+		stat      *decor.Statistics
+		want      string
+	}{
+		{
+			decorator: decor.OnComplete(plainDecorator("running"), "done"),
+			stat:      &decor.Statistics{},
+			want:      "running",
+		},
+		{
+			decorator: decor.OnComplete(plainDecorator("running"), "done"),
+			stat:      &decor.Statistics{Completed: true},
+			want:      "done",
+		},
+		{
+			decorator: decor.OnAbort(decor.OnComplete(decor.Name("eta", decor.WC{W: 8}), "done"), "failed"),
+			stat:      &decor.Statistics{Completed: true},
+			want:      "    done",
+		},
+		{
+			decorator: decor.OnAbort(decor.OnComplete(decor.Name("eta", decor.WC{W: 8}), "done"), "failed"),
+			stat:      aborted,
+			want:      "  failed",
+		},
+		{
+			decorator: decor.OnComplete(decor.OnAbort(decor.Name("eta", decor.WC{W: 8, C: decor.DidentRight}), ""), "done"),
+			stat:      aborted,
+			want:      "timeout ",
+		},
+	}
+
+	for _, test := range tests {
+		got := test.decorator.Decor(test.stat)
+		if got != test.want {
+			t.Errorf("Want: %q, Got: %q\n", test.want, got)
+		}
+	}
+}
+
+// plainDecorator doesn't implement decor.OnCompleteMessenger.
+type plainDecorator string
+
+func (d plainDecorator) Decor(*decor.Statistics) string { return string(d) }
+func (plainDecorator) Syncable() (bool, chan int)       { return false, nil }
+func (plainDecorator) FinishSync(int)                   {}
+
+func TestColorizeDecorator(t *testing.T) {
+	tests := []struct {
+		decorator decor.Decorator
+		color     bool
+		want      string
+	}{
+		{
+			decorator: decor.Colorize(decor.Name("Test"), decor.ColorGreen),
+			want:      "Test",
+		},
+		{
+			decorator: decor.Colorize(decor.Name("Test", decor.WC{W: 6}), decor.ColorGreen),
+			color:     true,
+			want:      "\x1b[32m  Test\x1b[0m",
+		},
+	}
+
+	for _, test := range tests {
+		got := test.decorator.Decor(&decor.Statistics{Color: test.color})
+		if got != test.want {
+			t.Errorf("Want: %q, Got: %q\n", test.want, got)
+		}
+	}
+}
+
+func TestAttemptsDecorator(t *testing.T) {
+	tests := []struct {
+		decorator decor.Decorator
+		attempts  int
+		want      string
+	}{
+		{
+			decorator: decor.Attempts("try %d"),
+			attempts:  1,
+			want:      "try 1",
+		},
+		{
+			decorator: decor.Attempts("try %d/5", decor.WC{W: 10}),
+			attempts:  3,
+			want:      "   try 3/5",
+		},
+	}
+
+	for _, test := range tests {
+		got := test.decorator.Decor(&decor.Statistics{Attempts: test.attempts})
+		if got != test.want {
+			t.Errorf("Want: %q, Got: %q\n", test.want, got)
+		}
+	}
+}
+
 type step struct {
 	stat      *decor.Statistics
 	decorator decor.Decorator
@@ -133,6 +289,26 @@ func TestPercentageDwidthSyncDidentRight(t *testing.T) {
 	testDecoratorConcurrently(t, testCases)
 }
 
+func TestPercentageDwidthSyncMinWidth(t *testing.T) {
+
+	testCases := [][]step{
+		[]step{
+			{
+				&decor.Statistics{Total: 100, Current: 8},
+				decor.Percentage(decor.WC{W: 5, C: decor.DSyncWidth}),
+				"  8 %",
+			},
+			{
+				&decor.Statistics{Total: 100, Current: 9},
+				decor.Percentage(decor.WC{C: decor.DSyncWidth, Align: decor.AlignCenter}),
+				" 9 % ",
+			},
+		},
+	}
+
+	testDecoratorConcurrently(t, testCases)
+}
+
 func TestPercentageDSyncSpace(t *testing.T) {
 
 	testCases := [][]step{
@@ -2,7 +2,14 @@ package mpb
 
 import (
 	"bytes"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/vbauerster/mpb/decor"
+	"github.com/vbauerster/mpb/internal"
 )
 
 func TestDraw(t *testing.T) {
@@ -36,7 +43,7 @@ func TestDraw(t *testing.T) {
 				total:     100,
 				current:   40,
 				barWidth:  100,
-				barRefill: &refill{'+', 32},
+				barRefill: &refill{'+', 32, ""},
 				want:      "[+++++++++++++++++++++++++++++++=======>-----------------------------------------------------------]",
 			},
 			"t,c,bw{100,99,100}": {
@@ -199,6 +206,476 @@ func TestDraw(t *testing.T) {
 	}
 }
 
+func TestDrawFinishing(t *testing.T) {
+	s := newTestState()
+	s.width = 10
+	s.total = 100
+	s.current = 100
+	s.finishing = true
+
+	var tmpBuf bytes.Buffer
+	for _, want := range []string{"[=======\\]", "[=======|]", "[=======/]", "[=======-]"} {
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(10))
+		want += "\n"
+		if got := tmpBuf.String(); got != want {
+			t.Errorf("want: %q, got: %q\n", want, got)
+		}
+	}
+}
+
+func TestDrawWrap(t *testing.T) {
+	s := newTestState()
+	s.width = 10
+	s.total = 100
+	s.current = 50
+	s.wrapMinWidth = 6
+	s.pDecorators = []decor.Decorator{decor.Name("/very/long/path")}
+	s.aDecorators = []decor.Decorator{decor.Name("50%")}
+
+	var tmpBuf bytes.Buffer
+	tmpBuf.ReadFrom(s.draw(12))
+	want := "/very/long/p\nath\n[===>---]50%\n"
+	if got := tmpBuf.String(); got != want {
+		t.Errorf("want: %q, got: %q\n", want, got)
+	}
+}
+
+func TestDrawTruncate(t *testing.T) {
+	s := newTestState()
+	s.width = 10
+	s.total = 100
+	s.current = 50
+	s.pDecorators = []decor.Decorator{decor.Name("/very/long/path")}
+	s.aDecorators = []decor.Decorator{decor.Name("50%")}
+
+	var tmpBuf bytes.Buffer
+	tmpBuf.ReadFrom(s.draw(12))
+	want := "/very/long/…\n"
+	if got := tmpBuf.String(); got != want {
+		t.Errorf("want: %q, got: %q\n", want, got)
+	}
+}
+
+func TestPausedEstimate(t *testing.T) {
+	s := newTestState()
+	s.total = 100
+	s.current = 50
+	now := time.Now()
+	s.startTime = now.Add(-400 * time.Millisecond)
+	s.pausedAt = now.Add(-300 * time.Millisecond)
+
+	// only 100ms, the bar has been running for, counts
+	if eta := s.estimate(); eta != 100*time.Millisecond {
+		t.Errorf("want eta %s, got %s", 100*time.Millisecond, eta)
+	}
+}
+
+func TestDrawFlashOnComplete(t *testing.T) {
+	s := newTestState()
+	s.width = 5
+	s.total = 100
+	s.current = 100
+	s.toComplete = true
+	s.flashFrames = 2
+	s.color = true
+
+	var tmpBuf bytes.Buffer
+	for _, want := range []string{"\x1b[7m[===]\x1b[0m", "\x1b[7m[===]\x1b[0m", "[===]"} {
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(5))
+		want += "\n"
+		if got := tmpBuf.String(); got != want {
+			t.Errorf("want: %q, got: %q\n", want, got)
+		}
+	}
+}
+
+func TestDrawStyleColor(t *testing.T) {
+	tests := []struct {
+		color, aborted bool
+		empty          string
+		want           string
+	}{
+		{want: "[=>---]"},
+		{color: true, want: "[\x1b[32m=>\x1b[0m---]"},
+		{color: true, empty: "\x1b[34m", want: "[\x1b[32m=>\x1b[0m\x1b[34m---\x1b[0m]"},
+		{color: true, aborted: true, want: "[\x1b[31m=>\x1b[0m---]"},
+	}
+
+	var tmpBuf bytes.Buffer
+	for _, test := range tests {
+		s := newTestState()
+		s.width = 7
+		s.total = 100
+		s.current = 40
+		s.color = test.color
+		s.aborted = test.aborted
+		s.fillColor, s.emptyColor, s.abortColor = "\x1b[32m", test.empty, "\x1b[31m"
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(7))
+		want := test.want + "\n"
+		if got := tmpBuf.String(); got != want {
+			t.Errorf("want: %q, got: %q\n", want, got)
+		}
+	}
+}
+
+func TestDrawRefillColor(t *testing.T) {
+	tests := []struct {
+		current int64
+		want    string
+	}{
+		{current: 60, want: "[\x1b[34m+++\x1b[0m==>----]"},
+		{current: 30, want: "[\x1b[34m++\x1b[0m>-------]"},
+	}
+
+	var tmpBuf bytes.Buffer
+	for _, test := range tests {
+		s := newTestState()
+		s.width = 12
+		s.total = 100
+		s.current = test.current
+		s.color = true
+		s.refill = &refill{'+', 30, "\x1b[34m"}
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(12))
+		want := test.want + "\n"
+		if got := tmpBuf.String(); got != want {
+			t.Errorf("want: %q, got: %q\n", want, got)
+		}
+	}
+}
+
+func TestRefilledAmount(t *testing.T) {
+	s := newTestState()
+	s.total = 100
+	s.refill = &refill{'+', 30, ""}
+	var amounts []int
+	s.amountReceivers = []decor.AmountReceiver{amountFunc(func(n int) {
+		amounts = append(amounts, n)
+	})}
+
+	s.incrBy(20)
+	s.incrBy(20)
+	s.incrBy(20)
+
+	if got := newStatistics(s).Refilled; got != 30 {
+		t.Errorf("want refilled 30, got %d", got)
+	}
+	if fmt.Sprint(amounts) != "[10 20]" {
+		t.Errorf("want amounts [10 20], got %v", amounts)
+	}
+}
+
+func TestIncrByBeyondTotal(t *testing.T) {
+	s := newTestState()
+	s.total = 100
+	s.refill = &refill{'+', 95, ""}
+	var amounts []int
+	s.amountReceivers = []decor.AmountReceiver{amountFunc(func(n int) {
+		amounts = append(amounts, n)
+	})}
+
+	s.incrBy(90)
+	s.incrBy(50)
+	s.incrBy(5)
+
+	if s.current != 100 || !s.toComplete {
+		t.Errorf("want complete at 100, got %d", s.current)
+	}
+	// only 10 of 50 are done, 5 of them have been refilled
+	if fmt.Sprint(amounts) != "[5]" {
+		t.Errorf("want amounts [5], got %v", amounts)
+	}
+}
+
+type amountFunc func(int)
+
+func (f amountFunc) NextAmount(n int, _ ...time.Duration) { f(n) }
+
+func TestDrawSpinner(t *testing.T) {
+	tests := map[SpinnerPosition][]string{
+		SpinnerOnMiddle: {"foo-bar", "foo\\bar"},
+		SpinnerOnLeft:   {"-foobar", "\\foobar"},
+		SpinnerOnRight:  {"foobar-", "foobar\\"},
+	}
+
+	var tmpBuf bytes.Buffer
+	for pos, wants := range tests {
+		s := newTestState()
+		s.width = 10
+		s.spinner = true
+		s.spinnerPos = pos
+		s.pDecorators = []decor.Decorator{decor.Name("foo")}
+		s.aDecorators = []decor.Decorator{decor.Name("bar")}
+		for _, want := range wants {
+			tmpBuf.Reset()
+			tmpBuf.ReadFrom(s.draw(10))
+			want += "\n"
+			if got := tmpBuf.String(); got != want {
+				t.Errorf("pos %d; want: %q, got: %q\n", pos, want, got)
+			}
+		}
+	}
+}
+
+func TestDrawSpinnerTruncated(t *testing.T) {
+	for _, pos := range []SpinnerPosition{SpinnerOnMiddle, SpinnerOnLeft, SpinnerOnRight} {
+		s := newTestState()
+		s.width = 10
+		s.spinner = true
+		s.spinnerPos = pos
+		s.pDecorators = []decor.Decorator{decor.Name(strings.Repeat("x", 200))}
+		var tmpBuf bytes.Buffer
+		tmpBuf.ReadFrom(s.draw(40))
+		line := strings.TrimSuffix(tmpBuf.String(), "\n")
+		if got := internal.DisplayWidth(line); got != 40 {
+			t.Errorf("pos %d; want width 40, got %d: %q", pos, got, line)
+		}
+	}
+}
+
+func TestDrawBarStyle(t *testing.T) {
+	arrow := BarStyle{Left: "<", Fill: "=", Tip: "=>>", Empty: ".", Right: ">"}
+	tests := []struct {
+		style   BarStyle
+		current int64
+		want    string
+	}{
+		{StyleBlocks, 0, "│          │"},
+		{StyleBlocks, 1, "│          │"},
+		{StyleBlocks, 2, "│▏         │"},
+		{StyleBlocks, 55, "│█████▌    │"},
+		{StyleBlocks, 99, "│█████████▉│"},
+		{StyleBlocks, 100, "│██████████│"},
+		{arrow, 20, "<==........>"},
+		{arrow, 50, "<===>>.....>"},
+	}
+
+	var tmpBuf bytes.Buffer
+	for _, test := range tests {
+		s := newTestState()
+		s.width = 12
+		s.total = 100
+		s.current = test.current
+		s.style = test.style
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(12))
+		want := test.want + "\n"
+		if got := tmpBuf.String(); got != want {
+			t.Errorf("current %d; want: %q, got: %q\n", test.current, want, got)
+		}
+	}
+}
+
+func TestDrawReverse(t *testing.T) {
+	tests := []struct {
+		style   BarStyle
+		current int64
+		want    string
+	}{
+		{StyleDefault, 0, "[----------]"},
+		{StyleDefault, 40, "[------<===]"},
+		{StyleDefault, 100, "[==========]"},
+		{BarStyle{Left: "[", Fill: "=", Tip: "=>>", Empty: "-", Right: "]"}, 50, "[-----<<===]"},
+		{StyleBlocks, 55, "│    ██████│"},
+	}
+
+	var tmpBuf bytes.Buffer
+	for _, test := range tests {
+		s := newTestState()
+		s.width = 12
+		s.total = 100
+		s.current = test.current
+		s.style = test.style
+		s.reverse = true
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(12))
+		want := test.want + "\n"
+		if got := tmpBuf.String(); got != want {
+			t.Errorf("current %d; want: %q, got: %q\n", test.current, want, got)
+		}
+	}
+
+	s := newTestState()
+	s.width = 10
+	s.total = 80
+	s.chunks = []chunk{{0, 20, 20}, {20, 20, 10}, {40, 20, 0}, {60, 20, 10}}
+	s.current = 40
+	s.reverse = true
+	tmpBuf.Reset()
+	tmpBuf.ReadFrom(s.draw(10))
+	if got, want := tmpBuf.String(), "[-=---===]\n"; got != want {
+		t.Errorf("chunks; want: %q, got: %q\n", want, got)
+	}
+}
+
+func TestDrawAbortMark(t *testing.T) {
+	tests := []struct {
+		current int64
+		reverse bool
+		want    string
+	}{
+		{0, false, "[✗---------]"},
+		{50, false, "[====✗-----]"},
+		{50, true, "[-----✗====]"},
+		{100, false, "[=========✗]"},
+	}
+
+	var tmpBuf bytes.Buffer
+	for _, test := range tests {
+		s := newTestState()
+		s.width = 12
+		s.total = 100
+		s.current = test.current
+		s.reverse = test.reverse
+		s.abortMark = "✗"
+		s.aborted = true
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(12))
+		want := test.want + "\n"
+		if got := tmpBuf.String(); got != want {
+			t.Errorf("current %d; want: %q, got: %q\n", test.current, want, got)
+		}
+	}
+}
+
+func TestDrawSpinnerStyle(t *testing.T) {
+	s := newTestState()
+	s.width = 10
+	s.spinner = true
+	BarSpinnerStyle([]string{"⠋", "<=>"})(s)
+	s.pDecorators = []decor.Decorator{decor.Name("foo")}
+
+	var tmpBuf bytes.Buffer
+	for _, want := range []string{"foo⠋  ", "foo<=>", "foo⠋  "} {
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(10))
+		want += "\n"
+		if got := tmpBuf.String(); got != want {
+			t.Errorf("want: %q, got: %q\n", want, got)
+		}
+	}
+
+	BarSpinnerInterval(time.Hour)(s)
+	for i := 0; i < 2; i++ {
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(10))
+		if got := tmpBuf.String(); got != "foo<=>\n" {
+			t.Errorf("want frame held within interval, got: %q\n", got)
+		}
+	}
+}
+
+func TestDrawChunks(t *testing.T) {
+	s := newTestState()
+	s.width = 10
+	s.total = 80
+	s.chunks = []chunk{{0, 20, 20}, {20, 20, 10}, {40, 20, 0}, {60, 20, 10}}
+	s.current = 40
+
+	var tmpBuf bytes.Buffer
+	tmpBuf.ReadFrom(s.draw(10))
+	want := "[===---=-]\n"
+	if got := tmpBuf.String(); got != want {
+		t.Errorf("want: %q, got: %q\n", want, got)
+	}
+}
+
+func TestTrackETA(t *testing.T) {
+	s := newTestState()
+	s.total = 100
+	s.current = 50
+
+	for i := 0; i < 3*maxETASamples; i++ {
+		s.trackETA()
+	}
+	if len(s.etaSamples) > maxETASamples {
+		t.Errorf("samples aren't bounded: %d", len(s.etaSamples))
+	}
+
+	// every prediction is off by one minute
+	for i := range s.etaSamples {
+		s.etaSamples[i] = time.Now().Add(time.Minute)
+	}
+	s.toComplete = true
+	s.trackETA()
+	if s.etaError < 59*time.Second || s.etaError > time.Minute {
+		t.Errorf("unexpected eta error: %s", s.etaError)
+	}
+}
+
+func TestDrawAggregateFailed(t *testing.T) {
+	tests := map[bool]string{
+		false: "[xx====>---]\n",
+		true:  "[\x1b[31m==\x1b[0m====>---]\n",
+	}
+
+	var tmpBuf bytes.Buffer
+	for color, want := range tests {
+		s := newTestState()
+		s.width = 12
+		s.total = 100
+		s.current = 60
+		s.aggregate = true
+		s.color = color
+		s.aggrCounts = aggrCounts{failed: 1, failedTotal: 20, failedCurrent: 10}
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(12))
+		if got := tmpBuf.String(); got != want {
+			t.Errorf("color %t; want: %q, got: %q\n", color, want, got)
+		}
+	}
+}
+
+func TestDrawMessageOnComplete(t *testing.T) {
+	s := newTestState()
+	s.width = 10
+	s.total = 100
+	s.current = 100
+	s.pDecorators = []decor.Decorator{decor.Name("foo", decor.WCSyncWidth)}
+	s.completeMsgFn = func(st *decor.Statistics) string {
+		return fmt.Sprintf("done %d", st.Current)
+	}
+
+	var tmpBuf bytes.Buffer
+	for _, want := range []string{"foo[========]\n", "done 100\n"} {
+		done := make(chan struct{})
+		// width sync must not block, even if decorators aren't called
+		go func() {
+			defer close(done)
+			_, ch := s.pDecorators[0].Syncable()
+			<-ch
+			ch <- 0
+		}()
+		tmpBuf.Reset()
+		tmpBuf.ReadFrom(s.draw(20))
+		<-done
+		if got := tmpBuf.String(); got != want {
+			t.Errorf("want: %q, got: %q\n", want, got)
+		}
+		s.toComplete = true
+	}
+}
+
+func TestDrawMessageOnCompleteTruncated(t *testing.T) {
+	s := newTestState()
+	s.width = 10
+	s.total = 100
+	s.current = 100
+	s.toComplete = true
+	s.completeMsgFn = func(*decor.Statistics) string {
+		return strings.Repeat("x", 200)
+	}
+
+	var tmpBuf bytes.Buffer
+	tmpBuf.ReadFrom(s.draw(20))
+	if want := strings.Repeat("x", 20) + "\n"; tmpBuf.String() != want {
+		t.Errorf("want: %q, got: %q\n", want, tmpBuf.String())
+	}
+}
+
 func newTestState() *bState {
 	s := &bState{
 		trimLeftSpace:  true,
@@ -207,6 +684,57 @@ func newTestState() *bState {
 		bufB:           new(bytes.Buffer),
 		bufA:           new(bytes.Buffer),
 	}
-	s.runes = strToBarRunes(pformat)
+	s.style = StyleDefault
 	return s
 }
+
+func TestSpeedStatistics(t *testing.T) {
+	s := newTestState()
+	s.total = 1000
+	s.current = 500
+	s.startTime = time.Now().Add(-time.Minute)
+	// paused, so elapsed is exactly 10s
+	s.pausedAt = s.startTime.Add(10 * time.Second)
+	s.speedSamples = []speedSample{{6 * time.Second, 0}, {7 * time.Second, 100}, {9 * time.Second, 300}}
+
+	st := newStatistics(s)
+	if st.CurrentSpeed != 125 {
+		t.Errorf("CurrentSpeed want: %v, got: %v\n", 125, st.CurrentSpeed)
+	}
+	if st.AverageSpeed != 50 {
+		t.Errorf("AverageSpeed want: %v, got: %v\n", 50, st.AverageSpeed)
+	}
+	if !st.SpeedSampled {
+		t.Error("SpeedSampled want: true, got: false")
+	}
+	s.speedSamples = s.speedSamples[:1]
+	if st := newStatistics(s); st.SpeedSampled || st.CurrentSpeed != 0 {
+		t.Errorf("single sample is taken for speed: %v", st.CurrentSpeed)
+	}
+	s.speedSamples = []speedSample{{6 * time.Second, 0}, {7 * time.Second, 100}, {9 * time.Second, 300}}
+
+	s.pausedAt = time.Time{}
+	s.startTime = time.Now().Add(-10 * time.Second)
+	s.speedWindow = 2 * time.Second
+	s.trackSpeed()
+	if len(s.speedSamples) != 3 || s.speedSamples[0].at != 7*time.Second {
+		t.Errorf("samples out of window aren't dropped: %v", s.speedSamples)
+	}
+}
+
+func TestClampInt(t *testing.T) {
+	tests := map[int64]int{
+		0:              0,
+		-1:             -1,
+		1 << 20:        1 << 20,
+		math.MaxInt64:  maxInt,
+		math.MinInt64:  minInt,
+		math.MaxInt32:  math.MaxInt32,
+		-math.MaxInt32: -math.MaxInt32,
+	}
+	for n, want := range tests {
+		if got := clampInt(n); got != want {
+			t.Errorf("clampInt(%d) want %d, got %d", n, want, got)
+		}
+	}
+}
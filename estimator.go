@@ -0,0 +1,105 @@
+package mpb
+
+import (
+	"math"
+	"time"
+
+	"github.com/VividCortex/ewma"
+	"github.com/vbauerster/mpb/decor"
+)
+
+// EtaEstimator estimates the bar's remaining time, see BarEtaEstimator.
+// Methods are called from the bar's goroutine only. Amounts, which don't
+// fit int on 32-bit platforms, are clamped to its range.
+type EtaEstimator interface {
+	// Update is called on each increment by n, which took dur to complete.
+	Update(n int, dur time.Duration)
+	// Eta returns estimated time to complete remaining amount.
+	Eta(remaining int) time.Duration
+}
+
+// BarEtaEstimator makes the bar to estimate its ETA by provided estimator,
+// instead of average rate since start. It drives decor.Statistics.ETA, so
+// decor.ETA decorator. Increment duration is taken from IncrBy's optional
+// work duration, if provided, otherwise it is time since previous increment.
+func BarEtaEstimator(estimator EtaEstimator) BarOption {
+	return func(s *bState) {
+		s.etaEstimator = estimator
+	}
+}
+
+// NewEwmaEstimator returns EtaEstimator, which averages per item duration
+// exponentially over previous age samples.
+func NewEwmaEstimator(age float64) EtaEstimator {
+	return &averageEstimator{average: ewma.NewMovingAverage(age)}
+}
+
+// NewMedianEstimator returns EtaEstimator, which takes median of last 3
+// per item durations, so single outliers of bursty workloads are ignored.
+func NewMedianEstimator() EtaEstimator {
+	return &averageEstimator{average: decor.NewMedian()}
+}
+
+type averageEstimator struct {
+	average decor.MovingAverage
+}
+
+func (e *averageEstimator) Update(n int, dur time.Duration) {
+	if n <= 0 {
+		return
+	}
+	e.average.Add(float64(dur) / float64(n))
+}
+
+func (e *averageEstimator) Eta(remaining int) time.Duration {
+	return time.Duration(float64(remaining) * e.average.Value())
+}
+
+// NewRegressionEstimator returns EtaEstimator, which fits a line through
+// last window points of cumulative progress over time, by least squares,
+// and extrapolates it to completion.
+func NewRegressionEstimator(window int) EtaEstimator {
+	if window < 2 {
+		window = 2
+	}
+	return &regressionEstimator{window: window}
+}
+
+type regressionEstimator struct {
+	window  int
+	elapsed time.Duration
+	amount  float64
+	// points of cumulative elapsed seconds and amount
+	ts, xs []float64
+}
+
+func (e *regressionEstimator) Update(n int, dur time.Duration) {
+	e.elapsed += dur
+	e.amount += float64(n)
+	e.ts = append(e.ts, e.elapsed.Seconds())
+	e.xs = append(e.xs, e.amount)
+	if len(e.ts) > e.window {
+		e.ts = e.ts[1:]
+		e.xs = e.xs[1:]
+	}
+}
+
+func (e *regressionEstimator) Eta(remaining int) time.Duration {
+	n := float64(len(e.ts))
+	if n < 2 {
+		return 0
+	}
+	var st, sx, stt, stx float64
+	for i, t := range e.ts {
+		st += t
+		sx += e.xs[i]
+		stt += t * t
+		stx += t * e.xs[i]
+	}
+	// slope is amount per second
+	slope := (n*stx - st*sx) / (n*stt - st*st)
+	if slope <= 0 || math.IsInf(slope, 0) || math.IsNaN(slope) {
+		return 0
+	}
+	return time.Duration(float64(remaining) / slope * float64(time.Second))
+}
@@ -0,0 +1,78 @@
+package mpb_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/vbauerster/mpb"
+	"github.com/vbauerster/mpb/decor"
+)
+
+func TestEtaEstimators(t *testing.T) {
+	tests := map[string]struct {
+		estimator EtaEstimator
+		durations []time.Duration
+		want      time.Duration
+	}{
+		"ewma": {
+			estimator: NewEwmaEstimator(30),
+			// variable ewma needs warm up samples
+			durations: repeat(10*time.Millisecond, 20),
+			want:      time.Second,
+		},
+		"median": {
+			estimator: NewMedianEstimator(),
+			durations: []time.Duration{10 * time.Millisecond, time.Second, 10 * time.Millisecond},
+			want:      time.Second,
+		},
+		"regression": {
+			estimator: NewRegressionEstimator(3),
+			durations: []time.Duration{time.Second, 10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond},
+			want:      time.Second,
+		},
+	}
+
+	for name, test := range tests {
+		for _, dur := range test.durations {
+			test.estimator.Update(1, dur)
+		}
+		got := test.estimator.Eta(100)
+		if diff := got - test.want; diff <= -time.Millisecond || diff >= time.Millisecond {
+			t.Errorf("%s: want %s, got %s", name, test.want, got)
+		}
+	}
+}
+
+func repeat(d time.Duration, n int) []time.Duration {
+	ds := make([]time.Duration, n)
+	for i := range ds {
+		ds[i] = d
+	}
+	return ds
+}
+
+type fixedEstimator time.Duration
+
+func (e fixedEstimator) Update(int, time.Duration) {}
+
+func (e fixedEstimator) Eta(int) time.Duration { return time.Duration(e) }
+
+func TestBarEtaEstimator(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	bar := p.AddBar(100, BarTrim(),
+		BarEtaEstimator(fixedEstimator(42*time.Second)),
+		AppendDecorators(decor.ETA(decor.ET_STYLE_GO)),
+	)
+	bar.IncrBy(50)
+	time.Sleep(200 * time.Millisecond)
+	bar.IncrBy(50)
+	p.Wait()
+
+	if !strings.Contains(buf.String(), "]42s") {
+		t.Errorf("estimator isn't used: %q", buf.String())
+	}
+}
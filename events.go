@@ -0,0 +1,79 @@
+package mpb
+
+import (
+	"fmt"
+	"time"
+)
+
+// BarEventType is type of BarEvent.
+type BarEventType string
+
+// BarEvent types
+const (
+	EventAdded     BarEventType = "added"
+	EventProgress  BarEventType = "progress"
+	EventCompleted BarEventType = "completed"
+	EventAborted   BarEventType = "aborted"
+)
+
+// BarEvent is written by Progress, as a line of JSON, to the event sink,
+// see WithEventSink. Its fields are taken from bar's decor.Statistics.
+type BarEvent struct {
+	Type     BarEventType `json:"type"`
+	Time     time.Time    `json:"time"`
+	ID       int          `json:"id"`
+	Total    int64        `json:"total"`
+	Current  int64        `json:"current"`
+	Refilled int64        `json:"refilled,omitempty"`
+	// ETA is in nanoseconds, zero once the bar is done.
+	ETA      time.Duration `json:"eta"`
+	Attempts int           `json:"attempts"`
+	// Reason is abort reason of aborted bar.
+	Reason string `json:"reason,omitempty"`
+}
+
+// barEvent writes event of provided type, unless it is EventProgress and
+// bar's current hasn't changed since its previous event.
+func (s *pState) barEvent(typ BarEventType, b *Bar) {
+	st := b.statistics()
+	if typ == EventProgress {
+		if last, ok := s.eventCurrent[b]; ok && last == st.Current {
+			return
+		}
+	}
+	if typ == EventCompleted && st.AbortReason != nil {
+		typ = EventAborted
+	}
+	now := time.Now()
+	if s.clock != nil {
+		now = s.clock.Now()
+	}
+	ev := BarEvent{
+		Type:     typ,
+		Time:     now,
+		ID:       st.ID,
+		Total:    st.Total,
+		Current:  st.Current,
+		Refilled: st.Refilled,
+		ETA:      st.ETA,
+		Attempts: st.Attempts,
+	}
+	switch typ {
+	case EventCompleted:
+		ev.ETA = 0
+		delete(s.eventCurrent, b)
+	case EventAborted:
+		ev.ETA = 0
+		reason := st.AbortReason
+		if reason == nil {
+			reason = ErrAborted
+		}
+		ev.Reason = reason.Error()
+		delete(s.eventCurrent, b)
+	default:
+		s.eventCurrent[b] = st.Current
+	}
+	if err := s.events.Encode(ev); err != nil {
+		fmt.Fprintf(s.debugOut, "%s %s %v\n", "[mpb]", time.Now(), err)
+	}
+}
@@ -1,3 +1,11 @@
 package mpb
 
-var SyncWidth = syncWidth
+import "io"
+
+func SyncWidth(matrix map[int][]chan int) {
+	syncWidth(matrix, 0, nil)
+}
+
+func (p *Progress) ForceRefresh(w io.Writer) {
+	p.forceRefresh(w)
+}
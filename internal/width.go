@@ -0,0 +1,263 @@
+package internal
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf8"
+)
+
+const esc = 0x1b
+
+// wide is a table of East Asian Wide and Fullwidth ranges,
+// which occupy two terminal cells.
+var wide = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115f, 1},
+		{0x231a, 0x231b, 1},
+		{0x2329, 0x232a, 1},
+		{0x23e9, 0x23ec, 1},
+		{0x23f0, 0x23f0, 1},
+		{0x23f3, 0x23f3, 1},
+		{0x25fd, 0x25fe, 1},
+		{0x2614, 0x2615, 1},
+		{0x2648, 0x2653, 1},
+		{0x267f, 0x267f, 1},
+		{0x2693, 0x2693, 1},
+		{0x26a1, 0x26a1, 1},
+		{0x26aa, 0x26ab, 1},
+		{0x26bd, 0x26be, 1},
+		{0x26c4, 0x26c5, 1},
+		{0x26ce, 0x26ce, 1},
+		{0x26d4, 0x26d4, 1},
+		{0x26ea, 0x26ea, 1},
+		{0x26f2, 0x26f3, 1},
+		{0x26f5, 0x26f5, 1},
+		{0x26fa, 0x26fa, 1},
+		{0x26fd, 0x26fd, 1},
+		{0x2705, 0x2705, 1},
+		{0x270a, 0x270b, 1},
+		{0x2728, 0x2728, 1},
+		{0x274c, 0x274c, 1},
+		{0x274e, 0x274e, 1},
+		{0x2753, 0x2755, 1},
+		{0x2757, 0x2757, 1},
+		{0x2795, 0x2797, 1},
+		{0x27b0, 0x27b0, 1},
+		{0x27bf, 0x27bf, 1},
+		{0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b50, 1},
+		{0x2b55, 0x2b55, 1},
+		{0x2e80, 0x303e, 1},
+		{0x3041, 0x33ff, 1},
+		{0x3400, 0x4dbf, 1},
+		{0x4e00, 0x9fff, 1},
+		{0xa000, 0xa4cf, 1},
+		{0xa960, 0xa97f, 1},
+		{0xac00, 0xd7a3, 1},
+		{0xf900, 0xfaff, 1},
+		{0xfe10, 0xfe19, 1},
+		{0xfe30, 0xfe6f, 1},
+		{0xff00, 0xff60, 1},
+		{0xffe0, 0xffe6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x16fe0, 0x16fe4, 1},
+		{0x17000, 0x18cff, 1},
+		{0x1b000, 0x1b2ff, 1},
+		{0x1f004, 0x1f004, 1},
+		{0x1f0cf, 0x1f0cf, 1},
+		{0x1f18e, 0x1f18e, 1},
+		{0x1f191, 0x1f19a, 1},
+		{0x1f200, 0x1f251, 1},
+		{0x1f300, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1},
+		{0x1f900, 0x1f9ff, 1},
+		{0x1fa70, 0x1faff, 1},
+		{0x20000, 0x2fffd, 1},
+		{0x30000, 0x3fffd, 1},
+	},
+}
+
+// RuneWidth returns number of terminal cells, occupied by r.
+func RuneWidth(r rune) int {
+	switch {
+	case r == 0 || r == '\u200b' || r == '\u200d' || r == '\ufeff':
+		return 0
+	case r < 0x20 || r == 0x7f:
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Variation_Selector):
+		return 0
+	case r >= 0x1100 && unicode.Is(wide, r):
+		return 2
+	}
+	return 1
+}
+
+// escapeLen returns length of ANSI escape sequence at the beginning of b,
+// or zero if b doesn't start with one. Both CSI and OSC sequences are recognized.
+func escapeLen(b string) int {
+	if len(b) < 2 || b[0] != esc {
+		return 0
+	}
+	switch b[1] {
+	case '[':
+		// CSI: parameter and intermediate bytes up to a final byte in 0x40-0x7e
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(b)
+	case ']':
+		// OSC: terminated by BEL or ST (ESC \)
+		for i := 2; i < len(b); i++ {
+			if b[i] == 0x07 {
+				return i + 1
+			}
+			if b[i] == esc && i+1 < len(b) && b[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(b)
+	}
+	return 2
+}
+
+// DisplayWidth returns number of terminal cells, s would occupy.
+// ANSI escape sequences are not counted, wide runes are counted as two cells.
+func DisplayWidth(s string) (width int) {
+	for len(s) > 0 {
+		if n := escapeLen(s); n > 0 {
+			s = s[n:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		width += RuneWidth(r)
+		s = s[size:]
+	}
+	return width
+}
+
+// DisplayWidthBytes is DisplayWidth of b, which spares converting b to
+// string.
+func DisplayWidthBytes(b []byte) (width int) {
+	for len(b) > 0 {
+		if c := b[0]; c < utf8.RuneSelf && c != esc {
+			width += RuneWidth(rune(c))
+			b = b[1:]
+			continue
+		}
+		if n := escapeLenBytes(b); n > 0 {
+			b = b[n:]
+			continue
+		}
+		r, size := utf8.DecodeRune(b)
+		width += RuneWidth(r)
+		b = b[size:]
+	}
+	return width
+}
+
+// escapeLenBytes is escapeLen of b.
+func escapeLenBytes(b []byte) int {
+	if len(b) < 2 || b[0] != esc {
+		return 0
+	}
+	switch b[1] {
+	case '[':
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(b)
+	case ']':
+		for i := 2; i < len(b); i++ {
+			if b[i] == 0x07 {
+				return i + 1
+			}
+			if b[i] == esc && i+1 < len(b) && b[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(b)
+	}
+	return 2
+}
+
+// StripEscapes appends b to dst, without ANSI escape sequences.
+func StripEscapes(dst, b []byte) []byte {
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, esc)
+		if i < 0 {
+			return append(dst, b...)
+		}
+		dst = append(dst, b[:i]...)
+		b = b[i:]
+		n := escapeLenBytes(b)
+		if n == 0 {
+			// lone ESC
+			n = 1
+		}
+		b = b[n:]
+	}
+	return dst
+}
+
+// Truncate cuts s, so its display width, including tail, doesn't exceed
+// width. tail is appended only if s was actually truncated. ANSI escape
+// sequences are preserved, so any style is reset as intended by s.
+func Truncate(s string, width int, tail string) string {
+	if DisplayWidth(s) <= width {
+		return s
+	}
+	width -= DisplayWidth(tail)
+	buf := make([]byte, 0, len(s))
+	var cut bool
+	for len(s) > 0 {
+		if n := escapeLen(s); n > 0 {
+			buf = append(buf, s[:n]...)
+			s = s[n:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		if w := RuneWidth(r); !cut && w > width {
+			buf = append(buf, tail...)
+			cut = true
+		} else if !cut {
+			width -= w
+			buf = append(buf, s[:size]...)
+		}
+		s = s[size:]
+	}
+	return string(buf)
+}
+
+// Wrap inserts new line, each time display width of s reaches width.
+// ANSI escape sequences are preserved and are not counted.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	buf := make([]byte, 0, len(s)+len(s)/width+1)
+	var lineWidth int
+	for len(s) > 0 {
+		if n := escapeLen(s); n > 0 {
+			buf = append(buf, s[:n]...)
+			s = s[n:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		w := RuneWidth(r)
+		if r == '\n' {
+			lineWidth = 0
+		} else if lineWidth+w > width {
+			buf = append(buf, '\n')
+			lineWidth = 0
+		}
+		lineWidth += w
+		buf = append(buf, s[:size]...)
+		s = s[size:]
+	}
+	return string(buf)
+}
@@ -0,0 +1,84 @@
+package internal
+
+import "testing"
+
+func TestDisplayWidth(t *testing.T) {
+	cases := map[string]struct {
+		s    string
+		want int
+	}{
+		"empty":       {"", 0},
+		"ascii":       {"foo", 3},
+		"unicode":     {"╢▌▌░╟", 5},
+		"cjk":         {"日本語", 6},
+		"csi":         {"\x1b[31mfoo\x1b[0m", 3},
+		"osc":         {"\x1b]0;title\x07foo", 3},
+		"combining":   {"é", 1},
+		"cjk and csi": {"\x1b[1m日本\x1b[0m!", 5},
+	}
+	for name, tc := range cases {
+		if got := DisplayWidth(tc.s); got != tc.want {
+			t.Errorf("%s: want: %d, got: %d\n", name, tc.want, got)
+		}
+		if got := DisplayWidthBytes([]byte(tc.s)); got != tc.want {
+			t.Errorf("%s bytes: want: %d, got: %d\n", name, tc.want, got)
+		}
+	}
+}
+
+func TestStripEscapes(t *testing.T) {
+	cases := map[string]struct {
+		s    string
+		want string
+	}{
+		"empty": {"", ""},
+		"ascii": {"foo", "foo"},
+		"csi":   {"\x1b[31mfoo\x1b[0m bar", "foo bar"},
+		"osc":   {"\x1b]0;title\x07foo", "foo"},
+		"lone":  {"foo\x1b", "foo"},
+	}
+	for name, tc := range cases {
+		if got := string(StripEscapes(nil, []byte(tc.s))); got != tc.want {
+			t.Errorf("%s: want: %q, got: %q\n", name, tc.want, got)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := map[string]struct {
+		s     string
+		width int
+		tail  string
+		want  string
+	}{
+		"fits":     {"foo", 3, "…", "foo"},
+		"ascii":    {"foobar", 4, "…", "foo…"},
+		"no tail":  {"foobar", 4, "", "foob"},
+		"cjk":      {"日本語", 5, "", "日本"},
+		"cjk tail": {"日本語", 4, "…", "日…"},
+		"csi":      {"\x1b[31mfoobar\x1b[0m", 3, "", "\x1b[31mfoo\x1b[0m"},
+	}
+	for name, tc := range cases {
+		if got := Truncate(tc.s, tc.width, tc.tail); got != tc.want {
+			t.Errorf("%s: want: %q, got: %q\n", name, tc.want, got)
+		}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	cases := map[string]struct {
+		s     string
+		width int
+		want  string
+	}{
+		"fits":  {"foo", 3, "foo"},
+		"ascii": {"foobar", 4, "foob\nar"},
+		"cjk":   {"日本語", 4, "日本\n語"},
+		"csi":   {"\x1b[31mfoobar\x1b[0m", 3, "\x1b[31mfoo\nbar\x1b[0m"},
+	}
+	for name, tc := range cases {
+		if got := Wrap(tc.s, tc.width); got != tc.want {
+			t.Errorf("%s: want: %q, got: %q\n", name, tc.want, got)
+		}
+	}
+}
@@ -1,12 +1,13 @@
 package mpb
 
 import (
+	"encoding/json"
 	"io"
 	"sync"
 	"time"
 	"unicode/utf8"
 
-	"github.com/vbauerster/mpb/cwriter"
+	"github.com/vbauerster/mpb/decor"
 )
 
 // ProgressOption is a function option which changes the default behavior of
@@ -23,6 +24,17 @@ func WithWaitGroup(wg *sync.WaitGroup) ProgressOption {
 	}
 }
 
+// WithShutdownTimeout makes Wait to abort bars, which are still running,
+// once d has passed, instead of waiting for them forever. Such bars are
+// reported as aborted with ErrShutdownTimeout. If user provided
+// *sync.WaitGroup isn't done by then, Wait stops waiting for it, though a
+// goroutine, parked in its Wait, is left behind, until it's done.
+func WithShutdownTimeout(d time.Duration) ProgressOption {
+	return func(s *pState) {
+		s.shutdownTimeout = d
+	}
+}
+
 // WithWidth overrides default width 80
 func WithWidth(w int) ProgressOption {
 	return func(s *pState) {
@@ -36,11 +48,19 @@ func WithWidth(w int) ProgressOption {
 func WithFormat(format string) ProgressOption {
 	return func(s *pState) {
 		if utf8.RuneCountInString(format) == formatLen {
-			s.format = format
+			s.style = StyleFromFormat(format)
 		}
 	}
 }
 
+// WithBarStyle overrides default bar style, it's WithFormat, which can
+// express multi-rune segments and smooth filling, like StyleBlocks does.
+func WithBarStyle(style BarStyle) ProgressOption {
+	return func(s *pState) {
+		s.style = style
+	}
+}
+
 // WithRefreshRate overrides default 120ms refresh rate
 func WithRefreshRate(d time.Duration) ProgressOption {
 	return func(s *pState) {
@@ -53,11 +73,33 @@ func WithRefreshRate(d time.Duration) ProgressOption {
 	}
 }
 
+// WithManualRefresh disables refresh rate ticker, bars are rendered upon
+// receive from provided channel or Progress.Refresh call only. Rendering is
+// handed back to the ticker, once the channel is closed or Wait is called.
+func WithManualRefresh(ch <-chan time.Time) ProgressOption {
+	return func(s *pState) {
+		s.manualRefresh = ch
+	}
+}
+
+// WithEventSink makes Progress write bars' events, like added, progress,
+// completed and aborted, to provided writer, as lines of JSON encoded
+// BarEvent. Progress event is written at most once per render cycle, if
+// bar's current has changed. Events are written in addition to rendering,
+// use WithOutput(ioutil.Discard) to have events only.
+func WithEventSink(w io.Writer) ProgressOption {
+	return func(s *pState) {
+		s.events = json.NewEncoder(w)
+		s.eventCurrent = make(map[*Bar]int64)
+	}
+}
+
 // WithCancel provide your cancel channel,
 // which you plan to close at some point.
 func WithCancel(ch <-chan struct{}) ProgressOption {
 	return func(s *pState) {
 		s.cancel = ch
+		s.cancelReason = nil
 	}
 }
 
@@ -74,7 +116,140 @@ func WithOutput(w io.Writer) ProgressOption {
 		if w == nil {
 			return
 		}
-		s.cw = cwriter.New(w)
+		s.output = w
+	}
+}
+
+// OutputMode defines how frames are written to the output.
+type OutputMode int
+
+const (
+	// ModeRedraw redraws bars in place, by moving cursor up. This is default.
+	ModeRedraw OutputMode = iota
+	// ModeAppend appends each frame to the output as plain text,
+	// no cursor movement is performed. Makes sense for non terminal
+	// outputs, like CI logs or files.
+	ModeAppend
+	// ModeAuto resolves to ModeRedraw, if output is a terminal and TERM
+	// isn't dumb, otherwise to ModeAppend.
+	ModeAuto
+)
+
+// WithOutputMode overrides default ModeRedraw output mode.
+func WithOutputMode(mode OutputMode) ProgressOption {
+	return func(s *pState) {
+		s.outputMode = mode
+	}
+}
+
+// WithAppendInterval limits frames, written in ModeAppend, to at most one
+// per provided interval, so logs aren't flooded at refresh rate. Frames
+// with completed bars are always written.
+func WithAppendInterval(d time.Duration) ProgressOption {
+	return func(s *pState) {
+		s.appendInterval = d
+	}
+}
+
+// WithFrameFilter provided func transforms lines of each assembled frame,
+// without trailing newlines, before the frame is written. Returned lines
+// may be added, removed or modified in place, for example to inject
+// separators or to redact secrets. Final frames, written to WithFinalOutput,
+// aren't filtered.
+func WithFrameFilter(filter func(lines [][]byte) [][]byte) ProgressOption {
+	return func(s *pState) {
+		s.frameFilter = filter
+	}
+}
+
+// WithAltScreen switches terminal to the alternate screen for the lifetime
+// of the container, rendering bars from the top of the screen below provided
+// header, if any. Original screen contents are restored on shutdown, so
+// bars are gone with it, use WithFinalOutput to keep their final frames.
+// Effective in ModeRedraw only.
+func WithAltScreen(header string) ProgressOption {
+	return func(s *pState) {
+		s.altScreen = true
+		s.altHeader = header
+	}
+}
+
+// WithTerminalTitle makes terminal window title to show overall progress
+// of bars, followed by provided name, if any, e.g. "42% — mytool". Title is
+// cleared on shutdown. Ineffective in ModeAppend.
+func WithTerminalTitle(name string) ProgressOption {
+	return func(s *pState) {
+		s.titled = true
+		s.titleName = name
+	}
+}
+
+// WithTimestamps prefixes each line of a frame with a timestamp,
+// formatted according to provided layout. If layout is empty,
+// time.RFC3339 is used. Effective in ModeAppend only.
+func WithTimestamps(layout string) ProgressOption {
+	return func(s *pState) {
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		s.timestampLayout = layout
+	}
+}
+
+// WithColorMode overrides default ColorAuto color mode. Resolved mode is
+// exposed to decorators via decor.Statistics.Color.
+func WithColorMode(mode ColorMode) ProgressOption {
+	return func(s *pState) {
+		s.colorMode = mode
+	}
+}
+
+// WithSortByKey makes bars order to be re-evaluated on each render cycle,
+// by provided key func, which overrides bar's priority. Lower key means
+// higher position, i.e. bar with the lowest key is on top.
+// For example, to show the most progressed bars on top:
+//
+//	mpb.WithSortByKey(func(st *decor.Statistics) int { return -int(100 * st.Current / st.Total) })
+func WithSortByKey(key func(*decor.Statistics) int) ProgressOption {
+	return func(s *pState) {
+		s.sortKey = key
+	}
+}
+
+// WithFrameBudget enables frame budget accounting. If rendering a frame
+// takes longer than budget, decorators of bars with lower priority are
+// evaluated less often, reusing their previous output meanwhile, until
+// frames fit into budget again. Usually budget is set to refresh rate.
+func WithFrameBudget(budget time.Duration) ProgressOption {
+	return func(s *pState) {
+		s.frameBudget = budget
+	}
+}
+
+// WithFinalOutput makes final frame of each completed bar to be written
+// to w as well, once. Gives a concise durable record, like a log file,
+// while intermediate frames go to the terminal only.
+func WithFinalOutput(w io.Writer) ProgressOption {
+	return func(s *pState) {
+		s.finalOutput = w
+	}
+}
+
+// WithClock makes all bars of the container, as well as frame timestamps
+// and bar events, to tell time by provided clock, see BarClock.
+func WithClock(c Clock) ProgressOption {
+	return func(s *pState) {
+		s.clock = c
+	}
+}
+
+// WithTee mirrors output to w as well, in plain text, like ModeAppend
+// writes it, i.e. each frame is appended with escape sequences stripped.
+// Frames are limited by WithAppendInterval, if set, regardless of output
+// mode. Gives a record of the whole run, like a log file for post-mortem.
+func WithTee(w io.Writer) ProgressOption {
+	return func(s *pState) {
+		s.tee = w
 	}
 }
 
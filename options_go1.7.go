@@ -4,12 +4,14 @@ package mpb
 
 import "context"
 
-// WithContext provided context will be used for cancellation purposes
+// WithContext provided context will be used for cancellation purposes.
+// Context's error becomes abort reason of cancelled bars.
 func WithContext(ctx context.Context) ProgressOption {
 	return func(s *pState) {
 		if ctx == nil {
 			panic("ctx must not be nil")
 		}
 		s.cancel = ctx.Done()
+		s.cancelReason = ctx.Err
 	}
 }
@@ -0,0 +1,72 @@
+package mpb
+
+import "time"
+
+// Pause freezes the bar's clock, so time spent paused isn't counted by ETA
+// estimation and by time measuring decorators, like decor.AverageETA or
+// decor.Elapsed, see decor.PauseListener. Progress may still be
+// incremented while paused. Paused state is exposed via
+// decor.Statistics.Paused, see decor.OnPause. Returns once the clock is
+// frozen, so a fake one, see BarClock, may be advanced right after.
+func (b *Bar) Pause() {
+	done := make(chan struct{})
+	select {
+	case b.operateState <- func(s *bState) {
+		defer close(done)
+		if !s.pausedAt.IsZero() {
+			return
+		}
+		s.pausedAt = s.now()
+		for _, pl := range s.pauseListeners {
+			pl.Pause()
+		}
+	}:
+		<-done
+	case <-b.done:
+	}
+}
+
+// Resume unfreezes the bar's clock, paused by Pause. Returns once the clock
+// is running.
+func (b *Bar) Resume() {
+	done := make(chan struct{})
+	select {
+	case b.operateState <- func(s *bState) {
+		defer close(done)
+		if s.pausedAt.IsZero() {
+			return
+		}
+		paused := s.now().Sub(s.pausedAt)
+		s.startTime = s.startTime.Add(paused)
+		if !s.lastIncr.IsZero() {
+			s.lastIncr = s.lastIncr.Add(paused)
+		}
+		// predicted finish times are delayed by pause
+		for i := range s.etaSamples {
+			s.etaSamples[i] = s.etaSamples[i].Add(paused)
+		}
+		s.pausedAt = time.Time{}
+		for _, pl := range s.pauseListeners {
+			pl.Resume()
+		}
+	}:
+		<-done
+	case <-b.done:
+	}
+}
+
+// elapsed returns time since start, excluding paused periods.
+func (s *bState) elapsed() time.Duration {
+	if !s.pausedAt.IsZero() {
+		return s.pausedAt.Sub(s.startTime)
+	}
+	return s.now().Sub(s.startTime)
+}
+
+// restartClock restarts the bar's clock at now, keeping it paused, if it is.
+func (s *bState) restartClock(now time.Time) {
+	s.startTime = now
+	if !s.pausedAt.IsZero() {
+		s.pausedAt = now
+	}
+}
@@ -0,0 +1,53 @@
+package mpb
+
+import "time"
+
+// SetPhase marks the start of named phase of the bar, like "fetch" or
+// "build", ending the current one, if any. Time spent in each phase is
+// exposed via decor.Statistics.PhaseDurations. Entering the same phase
+// again accumulates its duration. Empty name ends the current phase only.
+// The current phase ends, once the bar is complete. Returns once the phase
+// is switched.
+func (b *Bar) SetPhase(name string) {
+	done := make(chan struct{})
+	select {
+	case b.operateState <- func(s *bState) { s.switchPhase(name, s.now()); close(done) }:
+		<-done
+	case <-b.done:
+	}
+}
+
+func barPhase(name string) BarOption {
+	return func(s *bState) {
+		s.switchPhase(name, s.now())
+	}
+}
+
+func (s *bState) switchPhase(name string, now time.Time) {
+	if name == s.phase {
+		return
+	}
+	if s.phase != "" {
+		s.phaseDurations[s.phase] += now.Sub(s.phaseStart)
+	}
+	s.phase, s.phaseStart = name, now
+	if name != "" && s.phaseDurations == nil {
+		s.phaseDurations = make(map[string]time.Duration)
+	}
+}
+
+// phaseStatistics returns copy of phase durations,
+// including the current phase up to now.
+func (s *bState) phaseStatistics() map[string]time.Duration {
+	if s.phaseDurations == nil {
+		return nil
+	}
+	result := make(map[string]time.Duration, len(s.phaseDurations)+1)
+	for name, d := range s.phaseDurations {
+		result[name] = d
+	}
+	if s.phase != "" {
+		result[s.phase] += s.now().Sub(s.phaseStart)
+	}
+	return result
+}
@@ -0,0 +1,182 @@
+package mpb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/vbauerster/mpb/internal"
+)
+
+// Preview writes one static frame of all bars of the container to w, with
+// each bar drawn at synthetic progress, so styles and decorators can be
+// tried out, or captured in docs and tests, without running real
+// workloads. Neither bars' state nor container's output is affected, as
+// long as decorators, which keep state across frames, honor
+// decor.Statistics.Detached, like the ones of decor package do.
+// Provided fractions, in range [0, 1], are applied to bars in rendering
+// order, the rest of bars are spread evenly from empty to full.
+// Must not be called from a decorator.
+func (p *Progress) Preview(w io.Writer, fractions ...float64) error {
+	result := make(chan error, 1)
+	select {
+	case p.operateState <- func(s *pState) { result <- s.preview(w, fractions) }:
+		return <-result
+	case <-p.done:
+		return fmt.Errorf("mpb: preview of shutdown container")
+	}
+}
+
+// RenderFrame returns one frame of all bars of the container, exactly as
+// they are drawn at their current state, only without terminal escape
+// sequences. Meant for golden tests of bars' appearance. Neither bars'
+// state nor container's output is affected, see Preview. Returns nil, once the container
+// is shutdown. Must not be called from a decorator.
+func (p *Progress) RenderFrame() []byte {
+	result := make(chan []byte, 1)
+	select {
+	case p.operateState <- func(s *pState) {
+		_, frames := s.detachedFrames(nil)
+		result <- internal.StripEscapes(nil, bytes.Join(frames, nil))
+	}:
+		return <-result
+	case <-p.done:
+		return nil
+	}
+}
+
+// String returns bar's lines, as Progress.RenderFrame renders them, i.e.
+// with width of synced columns resolved against other bars. Returns empty
+// string, if the bar isn't rendered by the container, like removed or
+// queued one. Must not be called from a decorator.
+func (b *Bar) String() string {
+	result := make(chan string, 1)
+	select {
+	case b.container.operateState <- func(s *pState) {
+		bars, frames := s.detachedFrames(nil)
+		for i, bar := range bars {
+			if bar == b {
+				result <- string(internal.StripEscapes(nil, frames[i]))
+				return
+			}
+		}
+		result <- ""
+	}:
+		return <-result
+	case <-b.container.done:
+		return ""
+	}
+}
+
+func (s *pState) preview(w io.Writer, fractions []float64) error {
+	_, frames := s.detachedFrames(func(i, n int) func(*bState) {
+		fraction := float64(i+1) / float64(n+1)
+		if i < len(fractions) {
+			fraction = fractions[i]
+		}
+		return func(ps *bState) { ps.setFraction(fraction) }
+	})
+
+	var err error
+	for _, frame := range frames {
+		if _, e := w.Write(frame); e != nil && err == nil {
+			err = e
+		}
+	}
+	return err
+}
+
+// detachedFrames draws copies of bars' state, in rendering order. If setup
+// isn't nil, it provides, for i-th of n bars, func to adjust bar's copy
+// before it's drawn.
+func (s *pState) detachedFrames(setup func(i, n int) func(*bState)) ([]*Bar, [][]byte) {
+	if s.heapUpdated {
+		s.updateSyncMatrix()
+		s.heapUpdated = false
+	}
+	syncWidth(s.pMatrix, syncTimeout, s.syncTimeoutReporter("prepend", s.pIDs))
+	syncWidth(s.aMatrix, syncTimeout, s.syncTimeoutReporter("append", s.aIDs))
+
+	tw, e := s.cw.GetWidth()
+	if e != nil {
+		tw = s.width
+	}
+
+	bars := make([]*Bar, s.bHeap.Len())
+	copy(bars, *s.bHeap)
+	sort.SliceStable(bars, func(i, j int) bool {
+		return bars[i].priority < bars[j].priority
+	})
+
+	results := make([]chan []byte, len(bars))
+	for i, bar := range bars {
+		var fn func(*bState)
+		if setup != nil {
+			fn = setup(i, len(bars))
+		}
+		results[i] = make(chan []byte, 1)
+		go bar.detachedFrame(tw, fn, results[i])
+	}
+
+	frames := make([][]byte, len(bars))
+	for i, result := range results {
+		frames[i] = <-result
+	}
+	return bars, frames
+}
+
+func (b *Bar) detachedFrame(tw int, setup func(*bState), result chan<- []byte) {
+	select {
+	case b.operateState <- func(s *bState) { result <- s.detachedFrame(tw, setup) }:
+	case <-b.done:
+		result <- b.cacheState.detachedFrame(tw, setup)
+	}
+}
+
+// setFraction sets progress of the bar at provided fraction of total.
+func (s *bState) setFraction(fraction float64) {
+	if fraction < 0 {
+		fraction = 0
+	} else if fraction > 1 {
+		fraction = 1
+	}
+	if s.total > 0 {
+		s.current = int64(fraction * float64(s.total))
+		s.toComplete = s.current >= s.total
+	}
+}
+
+// detachedFrame draws a copy of the bar state, adjusted by setup, if it
+// isn't nil. Everything, which draw mutates, is detached from the original
+// state.
+func (s *bState) detachedFrame(tw int, setup func(*bState)) (frame []byte) {
+	ps := *s
+	ps.bufP, ps.bufB, ps.bufA = new(bytes.Buffer), new(bytes.Buffer), new(bytes.Buffer)
+	ps.pCache, ps.aCache, ps.etaSamples = nil, nil, nil
+	// trackSpeed trims samples in place
+	ps.speedSamples = append([]speedSample(nil), s.speedSamples...)
+	ps.detached = true
+	ps.phaseDurations, ps.phase = ps.phaseStatistics(), ""
+	ps.reuseDecor = false
+	if setup != nil {
+		ps.completeFlushed = false
+		ps.flashCount = ps.flashFrames
+		setup(&ps)
+	}
+
+	defer func() {
+		// user defined decorator may panic
+		if p := recover(); p != nil {
+			ps.finishSync()
+			frame = []byte(fmt.Sprintf(fmt.Sprintf("%%.%ds\n", tw), fmt.Sprintf("panic: %v", p)))
+		}
+	}()
+
+	var buf bytes.Buffer
+	buf.ReadFrom(ps.draw(tw))
+	if ps.extended() {
+		ps.extend(&buf, tw)
+	}
+	return buf.Bytes()
+}
@@ -34,7 +34,16 @@ func (pq *priorityQueue) Pop() interface{} {
 }
 
 // update modifies the priority of a Bar in the queue.
+// If the Bar isn't in the queue, only its priority is modified.
 func (pq *priorityQueue) update(bar *Bar, priority int) {
 	bar.priority = priority
+	if !pq.contains(bar) {
+		return
+	}
 	heap.Fix(pq, bar.index)
 }
+
+// contains reports whether the Bar is in the queue.
+func (pq *priorityQueue) contains(bar *Bar) bool {
+	return bar.index >= 0 && bar.index < len(*pq) && (*pq)[bar.index] == bar
+}
@@ -1,15 +1,23 @@
 package mpb
 
 import (
+	"bytes"
 	"container/heap"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	isatty "github.com/mattn/go-isatty"
 	"github.com/vbauerster/mpb/cwriter"
+	"github.com/vbauerster/mpb/decor"
+	"github.com/vbauerster/mpb/internal"
 )
 
 const (
@@ -17,16 +25,37 @@ const (
 	prr = 120 * time.Millisecond
 	// default width
 	pwidth = 80
-	// default format
-	pformat = "[=>-]"
+	// width sync column is reported, if not collected within syncTimeout
+	syncTimeout = time.Second
+	// max decay level, see WithFrameBudget
+	maxDecay = 4
 )
 
 // Progress represents the container that renders Progress bars
 type Progress struct {
-	wg           *sync.WaitGroup
-	uwg          *sync.WaitGroup
-	operateState chan func(*pState)
-	done         chan struct{}
+	// ioBytes is accessed atomically, must be 64-bit aligned
+	ioBytes int64
+	wg      *sync.WaitGroup
+	uwg     *sync.WaitGroup
+	// shutdownTimeout is how long Wait waits, before aborting bars
+	shutdownTimeout time.Duration
+	operateState    chan func(*pState)
+	done            chan struct{}
+	output          io.Writer
+	// err is written by master goroutine, before done is closed
+	err error
+}
+
+// Errors is returned by Progress.Close, it lists what has gone wrong while
+// rendering, like decorator panics, output write errors and aborted bars.
+type Errors []error
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
 }
 
 type pState struct {
@@ -36,19 +65,55 @@ type pState struct {
 	zeroWait        bool
 	idCounter       int
 	width           int
-	format          string
+	style           BarStyle
 	rr              time.Duration
+	output          io.Writer
+	outputMode      OutputMode
+	appendInterval  time.Duration
+	lastAppend      time.Time
+	altScreen       bool
+	altHeader       string
+	titled          bool
+	titleName       string
+	lastTitle       string
+	timestampLayout string
+	colorMode       ColorMode
+	color           bool
 	cw              *cwriter.Writer
 	ticker          *time.Ticker
+	manualRefresh   <-chan time.Time
 	pMatrix         map[int][]chan int
 	aMatrix         map[int][]chan int
+	pIDs            map[int][]int
+	aIDs            map[int][]int
+	aggregates      []*Bar
+	children        []*Bar
+	aggrBase        aggrRow
+	aggrCounts      aggrCounts
+	errs            Errors
+	flushFailed     bool
 
 	// following are provided by user
 	uwg              *sync.WaitGroup
+	shutdownTimeout  time.Duration
 	cancel           <-chan struct{}
+	cancelReason     func() error
 	shutdownNotifier chan struct{}
-	waitBars         map[*Bar]*Bar
+	waitBars         map[*Bar][]*Bar
+	hiddenBars       map[*Bar]struct{}
 	debugOut         io.Writer
+	sortKey          func(*decor.Statistics) int
+	frameBudget      time.Duration
+	decay            uint
+	frameCount       uint
+	held             bool
+	finalOutput      io.Writer
+	tee              io.Writer
+	clock            Clock
+	lastTee          time.Time
+	frameFilter      func([][]byte) [][]byte
+	events           *json.Encoder
+	eventCurrent     map[*Bar]int64
 }
 
 // New creates new Progress instance, which orchestrates bars rendering process.
@@ -57,14 +122,15 @@ func New(options ...ProgressOption) *Progress {
 	pq := make(priorityQueue, 0)
 	heap.Init(&pq)
 	s := &pState{
-		bHeap:    &pq,
-		width:    pwidth,
-		format:   pformat,
-		cw:       cwriter.New(os.Stdout),
-		rr:       prr,
-		ticker:   time.NewTicker(prr),
-		waitBars: make(map[*Bar]*Bar),
-		debugOut: ioutil.Discard,
+		bHeap:      &pq,
+		width:      pwidth,
+		style:      StyleDefault,
+		output:     os.Stdout,
+		rr:         prr,
+		ticker:     time.NewTicker(prr),
+		waitBars:   make(map[*Bar][]*Bar),
+		hiddenBars: make(map[*Bar]struct{}),
+		debugOut:   ioutil.Discard,
 	}
 
 	for _, opt := range options {
@@ -73,33 +139,56 @@ func New(options ...ProgressOption) *Progress {
 		}
 	}
 
+	if s.manualRefresh != nil {
+		s.ticker.Stop()
+	}
+
+	s.color = resolveColor(s.colorMode, s.output)
+	if s.outputMode == ModeAuto {
+		s.outputMode = resolveOutputMode(s.output)
+	}
+
+	if s.outputMode == ModeAppend {
+		out := s.output
+		if s.timestampLayout != "" {
+			out = &timestampWriter{out: out, layout: s.timestampLayout, clock: s.clock}
+		}
+		s.cw = cwriter.NewPlain(out)
+	} else if s.altScreen {
+		s.cw = cwriter.NewAltScreen(s.output, s.altHeader)
+	} else {
+		s.cw = cwriter.New(s.output)
+	}
+
 	p := &Progress{
-		uwg:          s.uwg,
-		wg:           new(sync.WaitGroup),
-		operateState: make(chan func(*pState)),
-		done:         make(chan struct{}),
+		uwg:             s.uwg,
+		shutdownTimeout: s.shutdownTimeout,
+		wg:              new(sync.WaitGroup),
+		operateState:    make(chan func(*pState)),
+		done:            make(chan struct{}),
+		output:          s.output,
 	}
 	go p.serve(s)
 	return p
 }
 
+// resolveOutputMode resolves ModeAuto against environment and output.
+func resolveOutputMode(out io.Writer) OutputMode {
+	if os.Getenv("TERM") == "dumb" {
+		return ModeAppend
+	}
+	if f, ok := out.(*os.File); ok && isatty.IsTerminal(f.Fd()) {
+		return ModeRedraw
+	}
+	return ModeAppend
+}
+
 // AddBar creates a new progress bar and adds to the container.
 func (p *Progress) AddBar(total int64, options ...BarOption) *Bar {
 	p.wg.Add(1)
 	result := make(chan *Bar)
 	select {
-	case p.operateState <- func(s *pState) {
-		options = append(options, barWidth(s.width), barFormat(s.format))
-		b := newBar(p.wg, s.idCounter, total, s.cancel, options...)
-		if b.runningBar != nil {
-			s.waitBars[b.runningBar] = b
-		} else {
-			heap.Push(s.bHeap, b)
-			s.heapUpdated = true
-		}
-		s.idCounter++
-		result <- b
-	}:
+	case p.operateState <- func(s *pState) { result <- s.addBar(p, total, options) }:
 		return <-result
 	case <-p.done:
 		p.wg.Done()
@@ -107,22 +196,113 @@ func (p *Progress) AddBar(total int64, options ...BarOption) *Bar {
 	}
 }
 
+// addBar creates a new bar and adds it to the heap, or to waitBars, if it's
+// queued after another bar.
+func (s *pState) addBar(p *Progress, total int64, options []BarOption) *Bar {
+	// container defaults go first, so bar's own options take precedence
+	options = append([]BarOption{barWidth(s.width), barStyle(s.style), barColor(s.color), barIOCounter(&p.ioBytes), barCancelReason(s.cancelReason), BarClock(s.clock)}, options...)
+	b := newBar(p.wg, s.idCounter, total, s.cancel, options...)
+	if b.runningBar != nil && !b.runningBar.finished {
+		s.waitBars[b.runningBar] = append(s.waitBars[b.runningBar], b)
+	} else {
+		s.pushBar(b)
+	}
+	if len(s.aggregates) > 0 {
+		s.children = append(s.children, b)
+	}
+	b.container = p
+	s.idCounter++
+	if s.events != nil {
+		s.barEvent(EventAdded, b)
+	}
+	return b
+}
+
 // Abort is only effective while bar progress is running,
 // it means remove bar now without waiting for its completion.
 // If bar is already completed, there is nothing to abort.
 // If you need to remove bar after completion, use BarRemoveOnComplete BarOption.
 func (p *Progress) Abort(b *Bar, remove bool) {
 	select {
-	case p.operateState <- func(s *pState) {
-		if b.index < 0 {
-			return
+	case p.operateState <- func(s *pState) { s.abortBar(b, remove) }:
+	case <-p.done:
+	}
+}
+
+func (s *pState) abortBar(b *Bar, remove bool) {
+	// hidden bar has to be in the heap to be shutdown
+	s.showBar(b)
+	if !s.bHeap.contains(b) && !s.unqueue(b) {
+		return
+	}
+	select {
+	case <-b.aborted:
+	default:
+		close(b.aborted)
+		s.errs = append(s.errs, b.abortError())
+		if s.events != nil && !b.finished {
+			s.barEvent(EventAborted, b)
 		}
-		if remove {
-			s.heapUpdated = heap.Remove(s.bHeap, b.index) != nil
+	}
+	if remove && s.bHeap.contains(b) {
+		s.heapUpdated = heap.Remove(s.bHeap, b.index) != nil
+	}
+	s.shutdownPending = append(s.shutdownPending, b)
+	// bars queued after aborted one would wait forever otherwise
+	s.releaseWaiting(b)
+}
+
+// unqueue removes the bar from waitBars, reports whether it has been there.
+// Aborted queued bar never starts, so it's never rendered.
+func (s *pState) unqueue(b *Bar) bool {
+	bars := s.waitBars[b.runningBar]
+	for i, wb := range bars {
+		if wb == b {
+			s.waitBars[b.runningBar] = append(bars[:i], bars[i+1:]...)
+			return true
 		}
-		s.shutdownPending = append(s.shutdownPending, b)
-	}:
-	case <-p.done:
+	}
+	return false
+}
+
+// pushBar adds the bar to the heap, or to hiddenBars, if it's hidden.
+func (s *pState) pushBar(b *Bar) {
+	if b.hidden {
+		b.index = -1
+		s.hiddenBars[b] = struct{}{}
+		return
+	}
+	heap.Push(s.bHeap, b)
+	s.heapUpdated = true
+}
+
+// showBar moves hidden bar back to the heap.
+func (s *pState) showBar(b *Bar) {
+	b.hidden = false
+	if _, ok := s.hiddenBars[b]; ok {
+		delete(s.hiddenBars, b)
+		s.pushBar(b)
+	}
+}
+
+// showHidden moves all hidden bars back to the heap.
+func (s *pState) showHidden() {
+	for b := range s.hiddenBars {
+		s.showBar(b)
+	}
+}
+
+// hideBar moves the bar out of the heap, bar queued after another one is
+// hidden, once started. Finished bar stays, so it can be shutdown.
+func (s *pState) hideBar(b *Bar) {
+	if b.finished {
+		return
+	}
+	b.hidden = true
+	if s.bHeap.contains(b) {
+		heap.Remove(s.bHeap, b.index)
+		s.heapUpdated = true
+		s.pushBar(b)
 	}
 }
 
@@ -135,7 +315,79 @@ func (p *Progress) UpdateBarPriority(b *Bar, priority int) {
 	}
 }
 
-// BarCount returns bars count
+// Hold suspends rendering, until Release is called. Intended for adding
+// many bars in a burst, so the first frame is rendered with all of them
+// present. Held bars can't complete, so Release must be called before Wait.
+func (p *Progress) Hold() {
+	select {
+	case p.operateState <- func(s *pState) { s.held = true }:
+	case <-p.done:
+	}
+}
+
+// Release resumes rendering, suspended by Hold, rendering a frame at once.
+func (p *Progress) Release() {
+	done := make(chan struct{})
+	select {
+	case p.operateState <- func(s *pState) {
+		defer close(done)
+		if !s.held {
+			return
+		}
+		s.held = false
+		tw, err := s.cw.GetWidth()
+		if err != nil {
+			tw = s.width
+		}
+		s.render(tw)
+	}:
+		<-done
+	case <-p.done:
+	}
+}
+
+// IOBytes returns total number of bytes, read or written via proxies of all
+// bars of the container. It is safe to call from a decorator, so it can be
+// used with decor.TotalSpeed.
+func (p *Progress) IOBytes() int64 {
+	return atomic.LoadInt64(&p.ioBytes)
+}
+
+// Write queues p to be written above the bars on the next render cycle,
+// so it doesn't corrupt them. Missing trailing newline is added. Once the
+// container is shutdown, p is written to the output directly. Progress is
+// an io.Writer, so it can be used with log.SetOutput for example.
+// Must not be called from a decorator.
+func (p *Progress) Write(b []byte) (int, error) {
+	line := make([]byte, len(b), len(b)+1)
+	copy(line, b)
+	if len(line) == 0 || line[len(line)-1] != '\n' {
+		line = append(line, '\n')
+	}
+	select {
+	case p.operateState <- func(s *pState) { s.cw.WriteAbove(line) }:
+		return len(b), nil
+	case <-p.done:
+		if _, err := p.output.Write(line); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+}
+
+// Println formats using the default formats for its operands and writes
+// resulting line above the bars, see Write.
+func (p *Progress) Println(a ...interface{}) {
+	p.Write([]byte(fmt.Sprintln(a...)))
+}
+
+// Printf formats according to a format specifier and writes resulting
+// line above the bars, see Write.
+func (p *Progress) Printf(format string, a ...interface{}) {
+	p.Write([]byte(fmt.Sprintf(format, a...)))
+}
+
+// BarCount returns bars count, hidden and queued bars aside.
 func (p *Progress) BarCount() int {
 	result := make(chan int, 1)
 	select {
@@ -148,12 +400,70 @@ func (p *Progress) BarCount() int {
 
 // Wait first waits for user provided *sync.WaitGroup, if any,
 // then waits far all bars to complete and finally shutdowns master goroutine.
+// Bars, which are still hidden, are shown, as they can't complete otherwise.
+// Each completed bar's final frame is flushed exactly once, before Wait
+// returns. With WithShutdownTimeout, bars still running, once the timeout
+// expires, are aborted with ErrShutdownTimeout, which Close then reports.
 // After this method has been called, there is no way to reuse *Progress instance.
 func (p *Progress) Wait() {
-	if p.uwg != nil {
-		p.uwg.Wait()
+	var timeout <-chan time.Time
+	if p.shutdownTimeout > 0 {
+		timer := time.NewTimer(p.shutdownTimeout)
+		defer timer.Stop()
+		timeout = timer.C
 	}
 
+	if p.uwg != nil && !waitTimeout(p.uwg, timeout) {
+		timeout = nil
+		p.abortRunning(ErrShutdownTimeout)
+	}
+
+	// bars can't complete without being rendered
+	select {
+	case p.operateState <- func(s *pState) { s.autoRefresh(); s.showHidden() }:
+	case <-p.done:
+	}
+
+	if !waitTimeout(p.wg, timeout) {
+		p.abortRunning(ErrShutdownTimeout)
+		p.wg.Wait()
+	}
+
+	select {
+	case p.operateState <- func(s *pState) { s.zeroWait = true }:
+		<-p.done
+	case <-p.done:
+	}
+}
+
+// waitTimeout waits for wg, reports false, if timeout has expired first.
+// Nil timeout never expires. There is no way to cancel wg.Wait, so once
+// timeout expires, it's left to return, whenever wg is done.
+func waitTimeout(wg *sync.WaitGroup, timeout <-chan time.Time) bool {
+	if timeout == nil {
+		wg.Wait()
+		return true
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-timeout:
+		return false
+	}
+}
+
+// Close implements io.Closer. It aborts bars, which are still running, waits
+// for all bars to exit, restores the terminal and shutdowns master goroutine.
+// Returned error is of Errors type, unless nothing has gone wrong while
+// rendering. Unlike Wait, it doesn't wait for user provided *sync.WaitGroup.
+func (p *Progress) Close() error {
+	p.abortRunning(nil)
+
 	p.wg.Wait()
 
 	select {
@@ -161,49 +471,252 @@ func (p *Progress) Wait() {
 		<-p.done
 	case <-p.done:
 	}
+	return p.err
+}
+
+// abortRunning aborts bars, which are still running, with provided reason,
+// ErrAborted if nil, and makes sure they are rendered, so they can exit.
+func (p *Progress) abortRunning(reason error) {
+	select {
+	case p.operateState <- func(s *pState) {
+		s.autoRefresh()
+		s.showHidden()
+		// queued bars have to be in the heap to be aborted
+		for waited, bars := range s.waitBars {
+			for _, bar := range bars {
+				heap.Push(s.bHeap, bar)
+			}
+			delete(s.waitBars, waited)
+			s.heapUpdated = true
+		}
+		for _, bar := range *s.bHeap {
+			if !bar.finished && !s.isAggregate(bar) && !bar.completing() {
+				if reason != nil {
+					bar.setAbortReason(reason)
+				}
+				s.abortBar(bar, false)
+			}
+		}
+	}:
+	case <-p.done:
+	}
 }
 
 func (s *pState) updateSyncMatrix() {
 	s.pMatrix = make(map[int][]chan int)
 	s.aMatrix = make(map[int][]chan int)
+	s.pIDs = make(map[int][]int)
+	s.aIDs = make(map[int][]int)
 	for i := 0; i < s.bHeap.Len(); i++ {
 		bar := (*s.bHeap)[i]
-		table := bar.wSyncTable()
+		id, table := bar.wSyncTable()
 		pRow, aRow := table[0], table[1]
 
 		for i, ch := range pRow {
 			s.pMatrix[i] = append(s.pMatrix[i], ch)
+			s.pIDs[i] = append(s.pIDs[i], id)
 		}
 
 		for i, ch := range aRow {
 			s.aMatrix[i] = append(s.aMatrix[i], ch)
+			s.aIDs[i] = append(s.aIDs[i], id)
 		}
 	}
 }
 
 func (s *pState) render(tw int) {
+	if s.held {
+		return
+	}
+	start := time.Now()
+	if len(s.aggregates) > 0 {
+		s.updateAggregates()
+	}
+	if s.sortKey != nil {
+		s.sortByKey()
+	}
 	if s.heapUpdated {
 		s.updateSyncMatrix()
 		s.heapUpdated = false
 	}
-	syncWidth(s.pMatrix)
-	syncWidth(s.aMatrix)
+	syncWidth(s.pMatrix, syncTimeout, s.syncTimeoutReporter("prepend", s.pIDs))
+	syncWidth(s.aMatrix, syncTimeout, s.syncTimeoutReporter("append", s.aIDs))
 
+	stale := s.staleBars()
 	for i := 0; i < s.bHeap.Len(); i++ {
 		bar := (*s.bHeap)[i]
-		go bar.render(s.debugOut, tw)
+		go bar.render(s.debugOut, tw, stale[bar])
+	}
+
+	if s.titled {
+		s.updateTitle()
 	}
 
 	if err := s.flush(); err != nil {
 		fmt.Fprintf(s.debugOut, "%s %s %v\n", "[mpb]", time.Now(), err)
+		// output is likely broken for good, so report it once
+		if !s.flushFailed {
+			s.flushFailed = true
+			s.errs = append(s.errs, err)
+		}
+	}
+
+	if s.frameBudget > 0 {
+		s.updateDecay(time.Since(start))
+	}
+}
+
+// staleBars returns bars, which should reuse their previous decorators
+// output during current frame. With decay level d, only top n>>d bars by
+// priority are decorated, while the rest are decorated every 1<<d frame.
+func (s *pState) staleBars() map[*Bar]bool {
+	s.frameCount++
+	if s.decay == 0 || s.frameCount%(1<<s.decay) == 0 {
+		return nil
+	}
+	bars := make([]*Bar, s.bHeap.Len())
+	copy(bars, *s.bHeap)
+	sort.Slice(bars, func(i, j int) bool {
+		return bars[i].priority < bars[j].priority
+	})
+	full := len(bars) >> s.decay
+	if full == 0 {
+		full = 1
+	}
+	stale := make(map[*Bar]bool, len(bars))
+	for _, bar := range bars[full:] {
+		stale[bar] = true
+	}
+	return stale
+}
+
+// updateDecay raises decay level, if frame took longer than frameBudget
+// and lowers it, once frames take less than half of it.
+func (s *pState) updateDecay(elapsed time.Duration) {
+	switch {
+	case elapsed > s.frameBudget && s.decay < maxDecay:
+		s.decay++
+	case elapsed < s.frameBudget/2 && s.decay > 0:
+		s.decay--
+	}
+}
+
+// sortByKey updates priority of each bar by sortKey and restores heap order.
+func (s *pState) sortByKey() {
+	var changed bool
+	for _, bar := range *s.bHeap {
+		priority := s.sortKey(bar.statistics())
+		if priority != bar.priority {
+			bar.priority = priority
+			changed = true
+		}
+	}
+	if changed {
+		heap.Init(s.bHeap)
+		s.heapUpdated = true
 	}
 }
 
+// releaseWaiting starts bars, which are waiting for provided bar to finish.
+// Reports whether any of them takes over the bar's line, see BarQueueAfter.
+func (s *pState) releaseWaiting(b *Bar) (takeOver bool) {
+	b.finished = true
+	for _, wb := range s.waitBars[b] {
+		s.pushBar(wb)
+		takeOver = takeOver || wb.takeOver
+	}
+	delete(s.waitBars, b)
+	return takeOver
+}
+
+// updateTitle sets window title to overall progress of rendered bars,
+// aggregate bars and bars of unknown total aside.
+func (s *pState) updateTitle() {
+	var total, current int64
+	for _, bar := range *s.bHeap {
+		if s.isAggregate(bar) {
+			continue
+		}
+		if row := bar.aggrRow(); !row.spinner {
+			total += row.total
+			current += row.current
+		}
+	}
+	title := fmt.Sprintf("%d%%", internal.Percentage(total, current, 100))
+	if s.titleName != "" {
+		title += " — " + s.titleName
+	}
+	if title != s.lastTitle {
+		s.lastTitle = title
+		s.cw.SetTitle(title)
+	}
+}
+
+// filterFrame writes frame lines, transformed by frameFilter, to cw.
+func (s *pState) filterFrame(frame []byte) {
+	var lines [][]byte
+	if len(frame) > 0 {
+		lines = bytes.Split(bytes.TrimSuffix(frame, []byte("\n")), []byte("\n"))
+	}
+	for _, line := range s.frameFilter(lines) {
+		s.cw.Write(line)
+		s.cw.Write([]byte("\n"))
+	}
+}
+
+// throttled reports whether current frame should be dropped,
+// according to appendInterval.
+func (s *pState) throttled() bool {
+	if s.outputMode != ModeAppend || s.appendInterval <= 0 || s.lastAppend.IsZero() {
+		return false
+	}
+	return time.Since(s.lastAppend) < s.appendInterval
+}
+
+// syncTimeoutReporter returns func, which reports decorator slot,
+// that blocks width sync column longer than syncTimeout.
+func (s *pState) syncTimeoutReporter(side string, ids map[int][]int) func(column, row int) {
+	debugOut := s.debugOut
+	return func(column, row int) {
+		fmt.Fprintf(debugOut, "%s %s width sync timeout: bar id %02d, %s decorator column %d\n",
+			"[mpb]", time.Now(), ids[column][row], side, column)
+	}
+}
+
+// flush writes frames of all bars, completed bar's frame is written
+// exactly once with completed state, before the bar is shutdown.
 func (s *pState) flush() (err error) {
+	var finalFrameHooks []func()
+	var final bool
+	var frameBuf bytes.Buffer
+	var dst io.ReaderFrom = s.cw
+	if s.frameFilter != nil {
+		dst = &frameBuf
+	}
 	for s.bHeap.Len() > 0 {
 		bar := heap.Pop(s.bHeap).(*Bar)
 		reader := <-bar.frameReaderCh
-		if _, e := s.cw.ReadFrom(reader); e != nil {
+		r := reader
+		if frame, ok := reader.(*frameReader); ok && frame.toShutdown {
+			final = true
+			if s.finalOutput != nil {
+				r = io.TeeReader(reader, s.finalOutput)
+			}
+			if frame.onFinalFrame != nil {
+				finalFrameHooks = append(finalFrameHooks, frame.onFinalFrame)
+			}
+		}
+		if frame, ok := reader.(*frameReader); ok && frame.err != nil {
+			s.errs = append(s.errs, frame.err)
+		}
+		if s.events != nil && !bar.finished && !s.isAggregate(bar) {
+			if frame, ok := reader.(*frameReader); ok && frame.toShutdown {
+				s.barEvent(EventCompleted, bar)
+			} else {
+				s.barEvent(EventProgress, bar)
+			}
+		}
+		if _, e := dst.ReadFrom(r); e != nil {
 			err = e
 		}
 		defer func() {
@@ -212,12 +725,7 @@ func (s *pState) flush() (err error) {
 				// only after the bar with completed state has been flushed.
 				// this ensures no bar ends up with less than 100% rendered.
 				s.shutdownPending = append(s.shutdownPending, bar)
-				if replacementBar, ok := s.waitBars[bar]; ok {
-					heap.Push(s.bHeap, replacementBar)
-					s.heapUpdated = true
-					delete(s.waitBars, bar)
-				}
-				if frame.removeOnComplete {
+				if s.releaseWaiting(bar) || frame.removeOnComplete {
 					s.heapUpdated = true
 					return
 				}
@@ -226,10 +734,34 @@ func (s *pState) flush() (err error) {
 		}()
 	}
 
+	if s.frameFilter != nil {
+		s.filterFrame(frameBuf.Bytes())
+	}
+
+	if s.throttled() && !final {
+		s.cw.Reset()
+	} else if s.outputMode == ModeAppend {
+		s.lastAppend = time.Now()
+	}
+
+	if s.tee != nil {
+		teeFrame := final || s.appendInterval <= 0 || time.Since(s.lastTee) >= s.appendInterval
+		if teeFrame {
+			s.lastTee = time.Now()
+		}
+		if e := s.cw.Tee(s.tee, teeFrame); err == nil {
+			err = e
+		}
+	}
+
 	if e := s.cw.Flush(); err == nil {
 		err = e
 	}
 
+	for _, fn := range finalFrameHooks {
+		fn()
+	}
+
 	for i := len(s.shutdownPending) - 1; i >= 0; i-- {
 		close(s.shutdownPending[i].shutdown)
 		s.shutdownPending = s.shutdownPending[:i]
@@ -237,13 +769,30 @@ func (s *pState) flush() (err error) {
 	return
 }
 
-func syncWidth(matrix map[int][]chan int) {
-	for _, column := range matrix {
-		column := column
+// syncWidth spawns goroutine per column, which collects widths of the
+// column, then sends max width back. If column isn't collected within
+// timeout, onTimeout is called with the first slot, which hasn't reported
+// yet, and collecting goes on. Zero timeout or nil onTimeout disables it.
+func syncWidth(matrix map[int][]chan int, timeout time.Duration, onTimeout func(column, row int)) {
+	for c, column := range matrix {
+		c, column := c, column
 		go func() {
+			var deadline <-chan time.Time
+			if timeout > 0 && onTimeout != nil {
+				timer := time.NewTimer(timeout)
+				defer timer.Stop()
+				deadline = timer.C
+			}
 			var maxWidth int
-			for _, ch := range column {
-				w := <-ch
+			for r, ch := range column {
+				var w int
+				select {
+				case w = <-ch:
+				case <-deadline:
+					onTimeout(c, r)
+					deadline = nil
+					w = <-ch
+				}
 				if w > maxWidth {
 					maxWidth = w
 				}
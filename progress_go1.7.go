@@ -0,0 +1,11 @@
+//+build go1.7
+
+package mpb
+
+import "context"
+
+// NewWithContext creates new Progress instance, like New does, which is
+// cancelled once provided context is done, see WithContext.
+func NewWithContext(ctx context.Context, options ...ProgressOption) *Progress {
+	return New(append([]ProgressOption{WithContext(ctx)}, options...)...)
+}
@@ -3,12 +3,16 @@
 package mpb_test
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/vbauerster/mpb"
+	"github.com/vbauerster/mpb/decor"
 )
 
 func TestWithContext(t *testing.T) {
@@ -48,3 +52,84 @@ func TestWithContext(t *testing.T) {
 		t.Error("Progress didn't stop")
 	}
 }
+
+func TestBarContext(t *testing.T) {
+	p := mpb.New(mpb.WithOutput(ioutil.Discard))
+
+	aborted := p.AddBar(100)
+	abortedCtx := aborted.Context(context.Background())
+	completed := p.AddBar(100)
+	completedCtx := completed.Context(context.Background())
+
+	p.Abort(aborted, true)
+	completed.IncrBy(100)
+	p.Wait()
+
+	select {
+	case <-abortedCtx.Done():
+	case <-time.After(100 * time.Millisecond):
+		t.Error("aborted bar's context isn't cancelled")
+	}
+	if err := completedCtx.Err(); err != nil {
+		t.Errorf("completed bar's context err: %v", err)
+	}
+}
+
+func TestBarContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := mpb.New(mpb.WithOutput(ioutil.Discard), mpb.WithContext(ctx))
+
+	bar := p.AddBar(100)
+	barCtx := bar.Context(context.Background())
+	cancel()
+	p.Wait()
+
+	select {
+	case <-barCtx.Done():
+	case <-time.After(100 * time.Millisecond):
+		t.Error("bar's context isn't cancelled on container cancel")
+	}
+}
+
+func TestNewWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var buf bytes.Buffer
+	p := mpb.NewWithContext(ctx, mpb.WithOutput(&buf), mpb.WithOutputMode(mpb.ModeAppend))
+
+	p.AddBar(100, mpb.BarTrim(), mpb.PrependDecorators(abortedDecorator()))
+	time.AfterFunc(100*time.Millisecond, cancel)
+	p.Wait()
+
+	if !strings.Contains(lastLine(buf.String()), "aborted 1 context canceled") {
+		t.Errorf("cancelled bar isn't marked aborted: %q", buf.String())
+	}
+}
+
+func TestBarAbortOnContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var buf bytes.Buffer
+	p := mpb.New(mpb.WithOutput(&buf), mpb.WithOutputMode(mpb.ModeAppend))
+
+	bar := p.AddBar(100, mpb.BarTrim(), mpb.PrependDecorators(abortedDecorator()))
+	bar.AbortOnContext(ctx)
+	completed := p.AddBar(100)
+	completed.AbortOnContext(context.Background())
+	completed.IncrBy(100)
+	time.AfterFunc(100*time.Millisecond, cancel)
+	p.Wait()
+
+	if !strings.Contains(buf.String(), "aborted 1 context canceled") {
+		t.Errorf("bar isn't marked aborted: %q", buf.String())
+	}
+}
+
+func abortedDecorator() decor.Decorator {
+	return decor.Any(func(st *decor.Statistics) string {
+		return fmt.Sprintf("aborted %d %v", st.Aborted, st.AbortReason)
+	})
+}
+
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	return lines[len(lines)-1]
+}
@@ -3,6 +3,7 @@
 package mpb
 
 import (
+	"fmt"
 	"os"
 	"os/signal"
 	"syscall"
@@ -24,6 +25,14 @@ func (p *Progress) serve(s *pState) {
 		case <-s.ticker.C:
 			if s.zeroWait {
 				s.ticker.Stop()
+				s.shutdownAggregates()
+				if err := s.cw.Close(); err != nil {
+					fmt.Fprintf(s.debugOut, "%s %s %v\n", "[mpb]", time.Now(), err)
+					s.errs = append(s.errs, err)
+				}
+				if len(s.errs) > 0 {
+					p.err = s.errs
+				}
 				signal.Stop(winch)
 				if s.shutdownNotifier != nil {
 					close(s.shutdownNotifier)
@@ -36,6 +45,16 @@ func (p *Progress) serve(s *pState) {
 				tw = s.width
 			}
 			s.render(tw)
+		case _, ok := <-s.manualRefresh:
+			if !ok {
+				s.autoRefresh()
+				break
+			}
+			tw, err := s.cw.GetWidth()
+			if err != nil {
+				tw = s.width
+			}
+			s.render(tw)
 		case <-winch:
 			tw, err := s.cw.GetWidth()
 			if err != nil {
@@ -49,7 +68,10 @@ func (p *Progress) serve(s *pState) {
 			timer = time.NewTimer(resumeDelay)
 			tickerResumer = timer.C
 		case <-tickerResumer:
-			s.ticker = time.NewTicker(s.rr)
+			if s.manualRefresh == nil {
+				s.ticker.Stop()
+				s.ticker = time.NewTicker(s.rr)
+			}
 			tickerResumer = nil
 			timer = nil
 		}
@@ -2,15 +2,23 @@ package mpb_test
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/rand"
+	"os"
+	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	. "github.com/vbauerster/mpb"
 	"github.com/vbauerster/mpb/cwriter"
+	"github.com/vbauerster/mpb/decor"
 )
 
 var (
@@ -147,3 +155,608 @@ func getLastLine(bb []byte) []byte {
 func randomDuration(max time.Duration) time.Duration {
 	return time.Duration(rand.Intn(10)+1) * max / 10
 }
+
+func TestWithTimestamps(t *testing.T) {
+	var buf bytes.Buffer
+	layout := "2006-01-02T15:04:05"
+	p := New(
+		WithOutput(&buf),
+		WithOutputMode(ModeAppend),
+		WithTimestamps(layout),
+	)
+	bar := p.AddBar(100, BarTrim())
+
+	for i := 0; i < 100; i++ {
+		time.Sleep(randomDuration(10 * time.Millisecond))
+		bar.Increment()
+	}
+
+	p.Wait()
+
+	if bytes.Contains(buf.Bytes(), []byte(clearCursorAndLine)) {
+		t.Error("ModeAppend output contains cursor movement sequence")
+	}
+
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+	for _, line := range lines {
+		if len(line) < len(layout) {
+			t.Fatalf("line too short: %q", line)
+		}
+		if _, err := time.Parse(layout, string(line[:len(layout)])); err != nil {
+			t.Errorf("line %q has no timestamp prefix: %v", line, err)
+		}
+	}
+}
+
+func TestWithTimestampsClock(t *testing.T) {
+	var buf bytes.Buffer
+	clock := &fakeClock{now: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}
+	p := New(
+		WithOutput(&buf),
+		WithOutputMode(ModeAppend),
+		WithTimestamps("2006-01-02T15:04:05"),
+		WithClock(clock),
+		WithManualRefresh(make(chan time.Time)),
+	)
+	bar := p.AddBar(100, BarTrim())
+	bar.IncrBy(100)
+	p.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "2020-01-02T03:04:05 ") {
+			t.Errorf("line %q isn't timestamped by clock", line)
+		}
+	}
+}
+
+func TestForceRefresh(t *testing.T) {
+	var buf, snap bytes.Buffer
+	p := New(WithOutput(&buf), WithWidth(10))
+	bar := p.AddBar(100, BarTrim())
+	bar.IncrBy(42)
+	spinner := p.AddBar(0, BarTrim())
+	spinner.IncrBy(7)
+
+	p.ForceRefresh(&snap)
+
+	if !bytes.Contains(buf.Bytes(), []byte("[==>-----]")) {
+		t.Errorf("forced refresh didn't render bar: %q", buf.String())
+	}
+	if !bytes.Contains(snap.Bytes(), []byte("bar id 00: 42/100 42%")) {
+		t.Errorf("unexpected snapshot: %q", snap.String())
+	}
+	if !bytes.Contains(snap.Bytes(), []byte("bar id 01: 7 eta")) {
+		t.Errorf("unexpected snapshot of unknown total bar: %q", snap.String())
+	}
+
+	bar.IncrBy(58)
+	spinner.SetTotal(7, true)
+	p.Wait()
+}
+
+func TestWithManualRefresh(t *testing.T) {
+	var buf bytes.Buffer
+	refresh := make(chan time.Time)
+	p := New(WithOutput(&buf), WithWidth(10), WithManualRefresh(refresh))
+	bar := p.AddBar(100, BarTrim())
+	bar.IncrBy(42)
+
+	time.Sleep(300 * time.Millisecond)
+	if p.BarCount(); buf.Len() != 0 {
+		t.Fatalf("rendered without refresh: %q", buf.String())
+	}
+
+	refresh <- time.Now()
+	if p.BarCount(); !bytes.Contains(buf.Bytes(), []byte("[==>-----]")) {
+		t.Errorf("refresh didn't render bar: %q", buf.String())
+	}
+
+	bar.IncrBy(8)
+	p.Refresh()
+	if !bytes.Contains(buf.Bytes(), []byte("[===>----]")) {
+		t.Errorf("Refresh didn't render bar: %q", buf.String())
+	}
+
+	bar.IncrBy(50)
+	p.Wait()
+}
+
+func TestWithEventSink(t *testing.T) {
+	var events bytes.Buffer
+	p := New(WithOutput(ioutil.Discard), WithEventSink(&events), WithManualRefresh(make(chan time.Time)))
+	done := p.AddBar(100)
+	aborted := p.AddBar(100)
+	done.IncrBy(100)
+	aborted.IncrBy(10)
+	aborted.AbortWithReason(errors.New("disk full"))
+	p.Wait()
+
+	var got []string
+	dec := json.NewDecoder(&events)
+	for {
+		var ev BarEvent
+		if err := dec.Decode(&ev); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, fmt.Sprintf("%d:%s:%d:%s", ev.ID, ev.Type, ev.Current, ev.Reason))
+	}
+	want := []string{
+		"0:added:0:",
+		"1:added:0:",
+		"1:aborted:10:disk full",
+		"0:completed:100:",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+func TestWithColorMode(t *testing.T) {
+	env := []string{"NO_COLOR", "FORCE_COLOR", "CLICOLOR", "CLICOLOR_FORCE"}
+	saved := make(map[string]string)
+	for _, k := range env {
+		saved[k] = os.Getenv(k)
+	}
+	defer func() {
+		for k, v := range saved {
+			os.Setenv(k, v)
+		}
+	}()
+
+	tests := map[string]struct {
+		mode  ColorMode
+		env   map[string]string
+		color bool
+	}{
+		"auto non tty":        {ColorAuto, nil, false},
+		"auto FORCE_COLOR":    {ColorAuto, map[string]string{"FORCE_COLOR": "1"}, true},
+		"auto FORCE_COLOR=0":  {ColorAuto, map[string]string{"FORCE_COLOR": "0"}, false},
+		"auto CLICOLOR_FORCE": {ColorAuto, map[string]string{"CLICOLOR_FORCE": "1"}, true},
+		"auto NO_COLOR wins":  {ColorAuto, map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, false},
+		"always NO_COLOR":     {ColorAlways, map[string]string{"NO_COLOR": "1"}, true},
+		"never FORCE_COLOR":   {ColorNever, map[string]string{"FORCE_COLOR": "1"}, false},
+	}
+
+	for name, tc := range tests {
+		for _, k := range env {
+			os.Setenv(k, tc.env[k])
+		}
+		var buf bytes.Buffer
+		p := New(WithOutput(&buf), WithColorMode(tc.mode))
+		bar := p.AddBar(10, BarFlashOnComplete(1))
+		bar.IncrBy(10)
+		p.Wait()
+
+		if got := bytes.Contains(buf.Bytes(), []byte("\x1b[7m")); got != tc.color {
+			t.Errorf("%s: color want %t, got %t", name, tc.color, got)
+		}
+	}
+}
+
+func TestWithSortByKey(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(
+		WithOutput(&buf),
+		WithOutputMode(ModeAppend),
+		WithSortByKey(func(st *decor.Statistics) int { return -int(st.Current) }),
+	)
+
+	a := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("a")))
+	b := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("b")))
+	a.IncrBy(10)
+	b.IncrBy(50)
+	time.Sleep(300 * time.Millisecond)
+	a.IncrBy(90)
+	b.IncrBy(50)
+	p.Wait()
+
+	lines := bytes.Split(bytes.TrimSuffix(buf.Bytes(), []byte("\n")), []byte("\n"))
+	if len(lines) < 4 {
+		t.Fatalf("too few lines: %q", buf.String())
+	}
+	// find frame, where b has been ahead of a
+	var found bool
+	for i := 0; i+1 < len(lines); i += 2 {
+		if bytes.HasPrefix(lines[i], []byte("b")) && bytes.Contains(lines[i], []byte("==>")) {
+			found = bytes.HasPrefix(lines[i+1], []byte("a"))
+			break
+		}
+	}
+	if !found {
+		t.Errorf("bar b wasn't on top, while ahead: %q", buf.String())
+	}
+}
+
+func TestWithFrameBudget(t *testing.T) {
+	p := New(
+		WithOutput(ioutil.Discard),
+		WithRefreshRate(10*time.Millisecond),
+		// every frame is over budget
+		WithFrameBudget(time.Nanosecond),
+	)
+
+	numBars := 4
+	counters := make([]*countingDecorator, numBars)
+	bars := make([]*Bar, numBars)
+	for i := 0; i < numBars; i++ {
+		counters[i] = &countingDecorator{}
+		counters[i].Init()
+		bars[i] = p.AddBar(100, AppendDecorators(counters[i]))
+	}
+
+	time.Sleep(500 * time.Millisecond)
+	for _, bar := range bars {
+		bar.IncrBy(100)
+	}
+	p.Wait()
+
+	top := atomic.LoadInt64(&counters[0].count)
+	low := atomic.LoadInt64(&counters[numBars-1].count)
+	if top <= 2*low {
+		t.Errorf("low priority bar decorated too often: top %d, low %d", top, low)
+	}
+}
+
+type countingDecorator struct {
+	decor.WC
+	count int64
+}
+
+func (d *countingDecorator) Decor(st *decor.Statistics) string {
+	return d.FormatMsg(fmt.Sprint(atomic.AddInt64(&d.count, 1)))
+}
+
+func TestHoldRelease(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	p.Hold()
+	bars := make([]*Bar, 3)
+	for i := range bars {
+		bars[i] = p.AddBar(100, BarTrim(), PrependDecorators(decor.Name(fmt.Sprint(i))))
+		time.Sleep(200 * time.Millisecond)
+	}
+	p.Release()
+
+	for _, bar := range bars {
+		bar.IncrBy(100)
+	}
+	p.Wait()
+
+	lines := bytes.Split(buf.Bytes(), []byte("\n"))
+	if len(lines) < len(bars) {
+		t.Fatalf("too few lines: %q", buf.String())
+	}
+	// first frame must contain all bars
+	for i := range bars {
+		if !bytes.HasPrefix(lines[i], []byte(fmt.Sprint(i))) {
+			t.Errorf("first frame line %d: %q", i, lines[i])
+		}
+	}
+}
+
+func TestWithFinalOutput(t *testing.T) {
+	var final bytes.Buffer
+	p := New(WithOutput(ioutil.Discard), WithFinalOutput(&final))
+
+	for i := 0; i < 2; i++ {
+		bar := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name(fmt.Sprintf("bar%d", i))))
+		go func() {
+			for j := 0; j < 10; j++ {
+				time.Sleep(randomDuration(50 * time.Millisecond))
+				bar.IncrBy(10)
+			}
+		}()
+	}
+	p.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(final.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 final lines, got %q", final.String())
+	}
+	for _, line := range lines {
+		if strings.Contains(line, "-") {
+			t.Errorf("final line isn't complete: %q", line)
+		}
+	}
+}
+
+func TestWithTee(t *testing.T) {
+	var out, tee bytes.Buffer
+	p := New(
+		WithOutput(&out),
+		WithOutputMode(ModeRedraw),
+		WithColorMode(ColorAlways),
+		WithWidth(10),
+		WithTee(&tee),
+		WithManualRefresh(make(chan time.Time)),
+	)
+	bar := p.AddBar(100, BarTrim(), BarStyleColor(decor.ColorGreen, "", ""))
+	bar.IncrBy(50)
+	p.Println("hello")
+	p.Refresh()
+	bar.IncrBy(50)
+	p.Wait()
+
+	if !strings.Contains(out.String(), "\x1b[") {
+		t.Errorf("output isn't redrawn in color: %q", out.String())
+	}
+	want := "hello\n[===>----]\n[========]\n"
+	if got := tee.String(); !strings.HasPrefix(got, want) || strings.Contains(got, "\x1b") {
+		t.Errorf("want prefix %q without escapes, got %q", want, got)
+	}
+}
+
+func TestBarSetPriority(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	a := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("a")))
+	b := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("b")))
+	b.SetPriority(-1)
+	time.Sleep(300 * time.Millisecond)
+	a.IncrBy(100)
+	b.IncrBy(100)
+	p.Wait()
+
+	lines := bytes.Split(buf.Bytes(), []byte("\n"))
+	if len(lines) < 2 || !bytes.HasPrefix(lines[0], []byte("b")) || !bytes.HasPrefix(lines[1], []byte("a")) {
+		t.Errorf("bar b isn't on top: %q", buf.String())
+	}
+}
+
+func TestWithAltScreen(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithAltScreen("Downloads"))
+
+	bar := p.AddBar(100, BarTrim())
+	bar.IncrBy(100)
+	p.Wait()
+
+	out := buf.String()
+	if !strings.HasPrefix(out, "\x1b[?1049h") {
+		t.Errorf("alternate screen isn't entered: %q", out)
+	}
+	if !strings.HasSuffix(out, "\x1b[?1049l") {
+		t.Errorf("original screen isn't restored: %q", out)
+	}
+	if !strings.Contains(out, "Downloads") {
+		t.Errorf("header is missing: %q", out)
+	}
+}
+
+func TestWithTerminalTitle(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithTerminalTitle("mytool"))
+
+	bar := p.AddBar(100, BarTrim())
+	bar.IncrBy(100)
+	p.Wait()
+
+	out := buf.String()
+	if !strings.Contains(out, "\x1b]0;100% — mytool\a") {
+		t.Errorf("title isn't set: %q", out)
+	}
+	if !strings.HasSuffix(out, "\x1b]0;\a") {
+		t.Errorf("title isn't cleared: %q", out)
+	}
+}
+
+func TestClose(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard), WithRefreshRate(10*time.Millisecond))
+
+	done := p.AddBar(10)
+	running := p.AddBar(10)
+	queued := p.AddBar(10, BarQueueAfter(running))
+	panicked := p.AddBar(100, PrependDecorators(panicDecorator("Upps!!!")))
+	done.IncrBy(10)
+	panicked.IncrBy(42)
+	running.IncrBy(5)
+	queued.IncrBy(5)
+	time.Sleep(100 * time.Millisecond)
+
+	err := p.Close()
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("want Errors, got %#v", err)
+	}
+	if len(errs) != 3 {
+		t.Errorf("want 3 errors, got %q", errs)
+	}
+	for _, want := range []string{"panic: Upps!!!", "bar id 01 aborted: mpb: bar aborted", "bar id 02 aborted"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%q doesn't contain %q", err, want)
+		}
+	}
+}
+
+func TestCloseNoErrors(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard))
+
+	bar := p.AddBar(10)
+	bar.IncrBy(10)
+
+	if err := p.Close(); err != nil {
+		t.Errorf("want nil error, got %v", err)
+	}
+}
+
+func TestPrintln(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend))
+
+	bar := p.AddBar(100, BarTrim())
+	p.Println("log", 1)
+	p.Printf("log %d", 2)
+	time.Sleep(300 * time.Millisecond)
+	bar.IncrBy(100)
+	p.Wait()
+	p.Println("log", 3)
+
+	out := buf.String()
+	first := strings.Index(out, "log 1\nlog 2\n")
+	if first < 0 {
+		t.Fatalf("queued lines are missing: %q", out)
+	}
+	if !strings.Contains(out[first:], "[") {
+		t.Errorf("queued lines aren't written above the bar: %q", out)
+	}
+	if !strings.HasSuffix(out, "log 3\n") {
+		t.Errorf("line after shutdown isn't written: %q", out)
+	}
+}
+
+func TestModeAutoAppendInterval(t *testing.T) {
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithOutputMode(ModeAuto), WithAppendInterval(time.Hour))
+
+	bar := p.AddBar(100, BarTrim())
+	for i := 0; i < 5; i++ {
+		time.Sleep(100 * time.Millisecond)
+		bar.IncrBy(20)
+	}
+	p.Wait()
+
+	out := buf.String()
+	if strings.ContainsRune(out, '\x1b') {
+		t.Errorf("escape sequence in non terminal output: %q", out)
+	}
+	// first frame and final one only
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("want 2 lines, got %q", out)
+	}
+	if strings.Contains(lines[1], "-") {
+		t.Errorf("final line isn't complete: %q", lines[1])
+	}
+}
+
+func TestWithFrameFilter(t *testing.T) {
+	var buf bytes.Buffer
+	filter := func(lines [][]byte) [][]byte {
+		for i, line := range lines {
+			lines[i] = bytes.Replace(line, []byte("secret"), []byte("******"), -1)
+		}
+		return append([][]byte{[]byte("---")}, lines...)
+	}
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend), WithFrameFilter(filter))
+
+	bar := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("secret")))
+	bar.IncrBy(100)
+	p.Wait()
+
+	out := buf.String()
+	if strings.Contains(out, "secret") {
+		t.Errorf("frame isn't filtered: %q", out)
+	}
+	if !strings.HasPrefix(out, "---\n******") {
+		t.Errorf("separator isn't injected: %q", out)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	var out bytes.Buffer
+	p := New(WithOutput(&out), WithWidth(20))
+
+	a := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("a", decor.WCSyncWidthR)), AppendDecorators(decor.Percentage(decor.WCSyncWidth)))
+	b := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("bb", decor.WCSyncWidthR)), AppendDecorators(decor.Percentage(decor.WCSyncWidth)))
+
+	var preview bytes.Buffer
+	if err := p.Preview(&preview, 0.5, 1); err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+	want := "a [=====>-----] 50 %\nbb[===========]100 %\n"
+	if got := preview.String(); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	if a.Current() != 0 || b.Current() != 0 {
+		t.Error("preview affected bars' state")
+	}
+	a.IncrBy(100)
+	b.IncrBy(100)
+	p.Wait()
+
+	if err := p.Preview(ioutil.Discard); err == nil {
+		t.Error("expected error after shutdown")
+	}
+}
+
+func TestRenderFrame(t *testing.T) {
+	var out bytes.Buffer
+	p := New(WithOutput(&out), WithWidth(20), WithManualRefresh(make(chan time.Time)))
+
+	a := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("a", decor.WCSyncWidthR)), AppendDecorators(decor.Percentage(decor.WCSyncWidth)))
+	b := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("bb", decor.WCSyncWidthR)), AppendDecorators(decor.Percentage(decor.WCSyncWidth)))
+	a.IncrBy(50)
+	b.IncrBy(100)
+
+	want := "a [=====>-----] 50 %\nbb[===========]100 %\n"
+	if got := string(p.RenderFrame()); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if got := a.String(); got != "a [=====>-----] 50 %\n" {
+		t.Errorf("unexpected a.String(): %q", got)
+	}
+	if p.BarCount(); out.Len() != 0 {
+		t.Errorf("RenderFrame wrote to output: %q", out.String())
+	}
+
+	a.IncrBy(50)
+	p.Wait()
+
+	if frame := p.RenderFrame(); frame != nil {
+		t.Errorf("expected nil frame after shutdown, got %q", frame)
+	}
+}
+
+func TestRenderFrameStripsEscapes(t *testing.T) {
+	p := New(WithOutput(ioutil.Discard), WithColorMode(ColorAlways), WithWidth(10), WithManualRefresh(make(chan time.Time)))
+
+	bar := p.AddBar(100, BarTrim(), BarStyleColor(decor.ColorGreen, "", ""))
+	bar.IncrBy(50)
+
+	want := "[===>----]\n"
+	if got := string(p.RenderFrame()); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if got := bar.String(); got != want {
+		t.Errorf("want bar.String() %q, got %q", want, got)
+	}
+
+	bar.IncrBy(50)
+	p.Wait()
+}
+
+func TestWithShutdownTimeout(t *testing.T) {
+	var buf bytes.Buffer
+	var wg sync.WaitGroup
+	// never done within Wait, as if a worker has got stuck
+	wg.Add(1)
+	defer wg.Done()
+	p := New(WithOutput(&buf), WithOutputMode(ModeAppend), WithWidth(10),
+		WithWaitGroup(&wg), WithManualRefresh(make(chan time.Time)), WithShutdownTimeout(time.Millisecond))
+
+	done := p.AddBar(100, BarTrim(), PrependDecorators(decor.Name("a")))
+	stuck := p.AddBar(100, BarTrim(), PrependDecorators(decor.OnAbort(decor.Name("b"), "x")))
+	done.IncrBy(100)
+	stuck.IncrBy(50)
+	// completed bar's final frame is flushed before the timeout expires
+	p.Refresh()
+
+	p.Wait()
+
+	err := p.Close()
+	if err == nil || !strings.Contains(err.Error(), ErrShutdownTimeout.Error()) {
+		t.Errorf("want error with %q, got %v", ErrShutdownTimeout, err)
+	}
+	if got := strings.Count(err.Error(), "aborted"); got != 1 {
+		t.Errorf("want only stuck bar aborted, got %v", err)
+	}
+	if want := "a[=======]\nx[===>---]\n"; !strings.HasSuffix(buf.String(), want) {
+		t.Errorf("want final frame %q, got %q", want, buf.String())
+	}
+}
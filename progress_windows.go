@@ -2,6 +2,11 @@
 
 package mpb
 
+import (
+	"fmt"
+	"time"
+)
+
 func (p *Progress) serve(s *pState) {
 	for {
 		select {
@@ -10,6 +15,14 @@ func (p *Progress) serve(s *pState) {
 		case <-s.ticker.C:
 			if s.zeroWait {
 				s.ticker.Stop()
+				s.shutdownAggregates()
+				if err := s.cw.Close(); err != nil {
+					fmt.Fprintf(s.debugOut, "%s %s %v\n", "[mpb]", time.Now(), err)
+					s.errs = append(s.errs, err)
+				}
+				if len(s.errs) > 0 {
+					p.err = s.errs
+				}
 				if s.shutdownNotifier != nil {
 					close(s.shutdownNotifier)
 				}
@@ -21,6 +34,16 @@ func (p *Progress) serve(s *pState) {
 				tw = s.width
 			}
 			s.render(tw)
+		case _, ok := <-s.manualRefresh:
+			if !ok {
+				s.autoRefresh()
+				break
+			}
+			tw, err := s.cw.GetWidth()
+			if err != nil {
+				tw = s.width
+			}
+			s.render(tw)
 		}
 	}
 }
@@ -1,23 +1,47 @@
 package mpb
 
-import (
-	"io"
-	"time"
-)
+import "io"
+
+// maxEmptyReads is number of consecutive reads without data and error,
+// after which chunkBuffer gives up with io.ErrNoProgress, as bufio does.
+const maxEmptyReads = 100
 
 // Reader is io.Reader wrapper, for proxy read bytes
 type Reader struct {
 	io.Reader
-	bar *Bar
+	bar   *Bar
+	chunk *chunkBuffer
 }
 
 func (r *Reader) Read(p []byte) (int, error) {
-	start := time.Now()
+	if r.chunk != nil {
+		return r.readChunk(p)
+	}
+	start := r.bar.now()
 	n, err := r.Reader.Read(p)
-	r.bar.IncrBy(n, time.Since(start))
+	r.bar.readBy(n, 1, r.bar.now().Sub(start))
 	return n, err
 }
 
+// readChunk serves p from the buffer, refilling it once it's drained.
+func (r *Reader) readChunk(p []byte) (int, error) {
+	c := r.chunk
+	if c.r == c.w {
+		if c.err != nil {
+			return 0, c.err
+		}
+		start := r.bar.now()
+		ops := c.fill(r.Reader)
+		r.bar.readBy(c.w, ops, r.bar.now().Sub(start))
+		if c.w == 0 {
+			return 0, c.err
+		}
+	}
+	n := copy(p, c.buf[c.r:c.w])
+	c.r += n
+	return n, nil
+}
+
 // Close the reader when it implements io.Closer
 func (r *Reader) Close() error {
 	if closer, ok := r.Reader.(io.Closer); ok {
@@ -25,3 +49,29 @@ func (r *Reader) Close() error {
 	}
 	return nil
 }
+
+// chunkBuffer accumulates small reads of the source into one chunk.
+type chunkBuffer struct {
+	buf  []byte
+	r, w int
+	err  error
+}
+
+// fill reads src into empty buffer, until it's full or src returns an
+// error, which is kept for when the buffer is drained. Returns number of
+// reads made.
+func (c *chunkBuffer) fill(src io.Reader) (ops int64) {
+	c.r, c.w = 0, 0
+	for empty := 0; c.w < len(c.buf) && c.err == nil; {
+		n, err := src.Read(c.buf[c.w:])
+		ops++
+		c.w += n
+		c.err = err
+		if n > 0 {
+			empty = 0
+		} else if empty++; empty == maxEmptyReads && err == nil {
+			c.err = io.ErrNoProgress
+		}
+	}
+	return ops
+}
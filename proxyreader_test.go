@@ -2,14 +2,19 @@ package mpb_test
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/vbauerster/mpb"
+	"github.com/vbauerster/mpb/decor"
 )
 
 const content = `Lorem ipsum dolor sit amet, consectetur adipisicing elit, sed do
@@ -79,6 +84,122 @@ func TestProxyReaderCloser(t *testing.T) {
 	}
 }
 
+func TestProxyReaderIOStats(t *testing.T) {
+	var buf bytes.Buffer
+	p := mpb.New(mpb.WithOutput(&buf))
+
+	total := len(content)
+	bar := p.AddBar(int64(total), mpb.BarTrim(),
+		mpb.AppendDecorators(decor.IOStats(0, " %d ops, avg %d")),
+	)
+	// read in fixed size chunks, so number of ops is known
+	preader := bar.ProxyReader(strings.NewReader(content))
+	chunk := make([]byte, 10)
+	for {
+		_, err := preader.Read(chunk)
+		if err == io.EOF {
+			break
+		}
+	}
+
+	p.Wait()
+
+	// final read at io.EOF returns no data, so it isn't counted
+	ops := total / 10
+	if total%10 != 0 {
+		ops++
+	}
+	want := fmt.Sprintf(" %d ops, avg %d", ops, total/ops)
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("%q doesn't contain %q\n", buf.String(), want)
+	}
+}
+
+// durationRecorder records work durations, the bar reports along with
+// amounts.
+type durationRecorder struct {
+	decor.Decorator
+	mu  sync.Mutex
+	wdd []time.Duration
+}
+
+func (d *durationRecorder) NextAmount(_ int, wdd ...time.Duration) {
+	d.mu.Lock()
+	d.wdd = append(d.wdd, wdd...)
+	d.mu.Unlock()
+}
+
+func (d *durationRecorder) durations() []time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.wdd
+}
+
+// tickReader advances clock by a second on each read.
+type tickReader struct {
+	io.Reader
+	clock *fakeClock
+}
+
+func (r tickReader) Read(p []byte) (int, error) {
+	r.clock.Add(time.Second)
+	return r.Reader.Read(p)
+}
+
+func TestProxyReaderClock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	p := mpb.New(mpb.WithOutput(ioutil.Discard), mpb.WithClock(clock))
+
+	rec := &durationRecorder{Decorator: decor.Name("")}
+	bar := p.AddBar(int64(len(content)), mpb.AppendDecorators(rec))
+	if _, err := io.Copy(ioutil.Discard, bar.ProxyReader(tickReader{strings.NewReader(content), clock})); err != nil {
+		t.Fatalf("Error copying from reader: %+v\n", err)
+	}
+
+	p.Wait()
+
+	wdd := rec.durations()
+	if len(wdd) == 0 {
+		t.Fatal("no durations reported")
+	}
+	for _, wd := range wdd {
+		if wd != time.Second {
+			t.Errorf("want duration by clock %v, got %v", time.Second, wd)
+		}
+	}
+}
+
+func TestProxyReaderSize(t *testing.T) {
+	var buf bytes.Buffer
+	p := mpb.New(mpb.WithOutput(&buf))
+
+	total := len(content)
+	bar := p.AddBar(int64(total), mpb.BarTrim(),
+		mpb.AppendDecorators(decor.Any(func(st *decor.Statistics) string {
+			return fmt.Sprintf(" %d/%d ops", st.ReadOps, st.SourceReadOps)
+		})),
+	)
+	// source yields a byte per read, so chunks are made of size reads
+	size := 100
+	preader := bar.ProxyReaderSize(iotest.OneByteReader(strings.NewReader(content)), size)
+	written, err := io.Copy(ioutil.Discard, preader)
+	if err != nil {
+		t.Errorf("Error copying from reader: %+v\n", err)
+	}
+
+	p.Wait()
+
+	if written != int64(total) {
+		t.Errorf("Expected written: %d, got: %d\n", total, written)
+	}
+	ops := (total + size - 1) / size
+	// the last source read is the one, which returns io.EOF
+	want := fmt.Sprintf(" %d/%d ops", ops, total+1)
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("%q doesn't contain %q\n", buf.String(), want)
+	}
+}
+
 func setupTestHttpServer(content string) *httptest.Server {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/test", func(w http.ResponseWriter, r *http.Request) {
@@ -86,3 +207,25 @@ func setupTestHttpServer(content string) *httptest.Server {
 	})
 	return httptest.NewServer(mux)
 }
+
+func TestProgressIOBytes(t *testing.T) {
+	var buf bytes.Buffer
+	p := mpb.New(mpb.WithOutput(&buf))
+
+	total := len(content)
+	for i := 0; i < 2; i++ {
+		bar := p.AddBar(int64(total), mpb.AppendDecorators(decor.TotalSpeed(p, decor.UnitKiB, "% .1f")))
+		go func() {
+			io.Copy(ioutil.Discard, bar.ProxyReader(strings.NewReader(content)))
+		}()
+	}
+
+	p.Wait()
+
+	if got := p.IOBytes(); got != int64(2*total) {
+		t.Errorf("Expected io bytes: %d, got: %d\n", 2*total, got)
+	}
+	if !strings.Contains(buf.String(), "/s") {
+		t.Errorf("TotalSpeed isn't rendered: %q\n", buf.String())
+	}
+}
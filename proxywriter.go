@@ -0,0 +1,50 @@
+package mpb
+
+import "io"
+
+// Writer is io.Writer wrapper, for proxy written bytes
+type Writer struct {
+	io.Writer
+	bar *Bar
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	start := w.bar.now()
+	n, err := w.Writer.Write(p)
+	w.bar.writeBy(n, w.bar.now().Sub(start))
+	return n, err
+}
+
+// Close the writer when it implements io.Closer
+func (w *Writer) Close() error {
+	if closer, ok := w.Writer.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+type readCloser struct {
+	*Reader
+}
+
+func (r *readCloser) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		r.bar.Complete()
+	}
+	return n, err
+}
+
+func (r *readCloser) Close() error {
+	defer r.bar.Complete()
+	return r.Reader.Close()
+}
+
+type writeCloser struct {
+	*Writer
+}
+
+func (w *writeCloser) Close() error {
+	defer w.bar.Complete()
+	return w.Writer.Close()
+}
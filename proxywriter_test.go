@@ -0,0 +1,117 @@
+package mpb_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vbauerster/mpb"
+	"github.com/vbauerster/mpb/decor"
+)
+
+func TestProxyWriter(t *testing.T) {
+	var buf bytes.Buffer
+	p := mpb.New(mpb.WithOutput(&buf))
+
+	total := len(content)
+	bar := p.AddBar(int64(total), mpb.BarTrim(),
+		mpb.AppendDecorators(decor.CountersNoUnit(" %d/%d")),
+	)
+
+	var dst bytes.Buffer
+	pwriter := bar.ProxyWriter(&dst)
+
+	written, err := io.Copy(pwriter, strings.NewReader(content))
+	if err != nil {
+		t.Errorf("Error copying to writer: %+v\n", err)
+	}
+
+	p.Wait()
+
+	if written != int64(total) || dst.String() != content {
+		t.Errorf("Expected written: %d, got: %d\n", total, written)
+	}
+
+	// underlying writer is not Closer
+	if err := pwriter.Close(); err != nil {
+		t.Errorf("Expected nil error, got: %+v\n", err)
+	}
+}
+
+// tickWriter advances clock by a second on each write.
+type tickWriter struct {
+	io.Writer
+	clock *fakeClock
+}
+
+func (w tickWriter) Write(p []byte) (int, error) {
+	w.clock.Add(time.Second)
+	return w.Writer.Write(p)
+}
+
+func TestProxyWriterClock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	p := mpb.New(mpb.WithOutput(ioutil.Discard), mpb.WithClock(clock))
+
+	rec := &durationRecorder{Decorator: decor.Name("")}
+	bar := p.AddBar(int64(len(content)), mpb.AppendDecorators(rec))
+	if _, err := io.Copy(bar.ProxyWriter(tickWriter{ioutil.Discard, clock}), strings.NewReader(content)); err != nil {
+		t.Fatalf("Error copying to writer: %+v\n", err)
+	}
+
+	p.Wait()
+
+	wdd := rec.durations()
+	if len(wdd) == 0 {
+		t.Fatal("no durations reported")
+	}
+	for _, wd := range wdd {
+		if wd != time.Second {
+			t.Errorf("want duration by clock %v, got %v", time.Second, wd)
+		}
+	}
+}
+
+func TestProxyReadCloserCompletesOnEOF(t *testing.T) {
+	p := mpb.New(mpb.WithOutput(ioutil.Discard))
+
+	// total is unknown
+	bar := p.AddBar(0)
+	rc := bar.ProxyReadCloser(ioutil.NopCloser(strings.NewReader(content)))
+
+	if _, err := io.Copy(ioutil.Discard, rc); err != nil {
+		t.Errorf("Error copying from reader: %+v\n", err)
+	}
+
+	p.Wait()
+
+	if got := bar.Current(); got != int64(len(content)) {
+		t.Errorf("Expected current: %d, got: %d\n", len(content), got)
+	}
+}
+
+func TestProxyWriteCloserCompletesOnClose(t *testing.T) {
+	p := mpb.New(mpb.WithOutput(ioutil.Discard))
+
+	// more than is going to be written
+	bar := p.AddBar(int64(2 * len(content)))
+	wc := bar.ProxyWriteCloser(nopWriteCloser{ioutil.Discard})
+
+	if _, err := io.WriteString(wc, content); err != nil {
+		t.Errorf("Error writing: %+v\n", err)
+	}
+	if err := wc.Close(); err != nil {
+		t.Errorf("Expected nil error, got: %+v\n", err)
+	}
+
+	p.Wait()
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,106 @@
+package mpb
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"time"
+)
+
+// ForceRefreshOn makes Progress listen for provided signals, like
+// syscall.SIGUSR1, upon which bars are redrawn immediately and plain
+// snapshot of every bar is written to os.Stderr. Listening stops, once
+// Progress is done. Does nothing without signals, as signal.Notify would
+// relay all of them otherwise, SIGINT included.
+func (p *Progress) ForceRefreshOn(sig ...os.Signal) {
+	if len(sig) == 0 {
+		return
+	}
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sig...)
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ch:
+				p.forceRefresh(os.Stderr)
+			case <-p.done:
+				return
+			}
+		}
+	}()
+}
+
+// Refresh renders bars immediately and returns, once they are written to
+// the output. See WithManualRefresh.
+func (p *Progress) Refresh() {
+	done := make(chan struct{})
+	select {
+	case p.operateState <- func(s *pState) {
+		defer close(done)
+		tw, err := s.cw.GetWidth()
+		if err != nil {
+			tw = s.width
+		}
+		s.render(tw)
+	}:
+		<-done
+	case <-p.done:
+	}
+}
+
+// autoRefresh hands rendering back to refresh rate ticker, if manual
+// refresh is on.
+func (s *pState) autoRefresh() {
+	if s.manualRefresh == nil {
+		return
+	}
+	s.manualRefresh = nil
+	s.ticker = time.NewTicker(s.rr)
+}
+
+func (p *Progress) forceRefresh(w io.Writer) {
+	done := make(chan struct{})
+	select {
+	case p.operateState <- func(s *pState) {
+		defer close(done)
+		tw, err := s.cw.GetWidth()
+		if err != nil {
+			tw = s.width
+		}
+		s.render(tw)
+		s.snapshot(w)
+	}:
+		<-done
+	case <-p.done:
+	}
+}
+
+// snapshot writes one plain line per bar, in bar's order. Total and
+// percentage of bars with unknown total are omitted.
+func (s *pState) snapshot(w io.Writer) {
+	bars := make([]*Bar, s.bHeap.Len())
+	copy(bars, *s.bHeap)
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].priority == bars[j].priority {
+			return bars[i].index < bars[j].index
+		}
+		return bars[i].priority < bars[j].priority
+	})
+	now := time.Now().Format(time.RFC3339)
+	for _, bar := range bars {
+		st := bar.statistics()
+		progress := fmt.Sprint(st.Current)
+		if !bar.aggrRow().spinner {
+			var percent int64
+			if st.Total > 0 {
+				percent = 100 * st.Current / st.Total
+			}
+			progress = fmt.Sprintf("%d/%d %d%%", st.Current, st.Total, percent)
+		}
+		fmt.Fprintf(w, "%s %s bar id %02d: %s eta %s\n",
+			"[mpb]", now, st.ID, progress, st.ETA/time.Second*time.Second)
+	}
+}
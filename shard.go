@@ -0,0 +1,49 @@
+package mpb
+
+import "sync/atomic"
+
+// Shard is an increment handle of a bar, see Bar.ShardedCounter.
+// Its methods are safe for concurrent use.
+type Shard struct {
+	// n is accessed atomically, must be 64-bit aligned
+	n int64
+}
+
+// IncrBy adds n to the shard, to be drained into the bar on next frame.
+func (sh *Shard) IncrBy(n int) {
+	atomic.AddInt64(&sh.n, int64(n))
+}
+
+// Increment is a shorthand for sh.IncrBy(1).
+func (sh *Shard) Increment() {
+	sh.IncrBy(1)
+}
+
+// ShardedCounter returns n independent increment handles of the bar. Each
+// handle accumulates increments locally with an atomic add, instead of a
+// channel send per call, as IncrBy does, so many workers hammering the same
+// bar don't contend with each other and with the renderer. Accumulated
+// amounts are drained into the bar once per frame, so Current may lag
+// behind for up to one refresh interval.
+func (b *Bar) ShardedCounter(n int) []*Shard {
+	shards := make([]*Shard, n)
+	for i := range shards {
+		shards[i] = new(Shard)
+	}
+	select {
+	case b.operateState <- func(s *bState) { s.shards = append(s.shards, shards...) }:
+	case <-b.done:
+	}
+	return shards
+}
+
+// drainShards moves increments, accumulated by shards, into the bar.
+func (s *bState) drainShards() {
+	var n int64
+	for _, sh := range s.shards {
+		n += atomic.SwapInt64(&sh.n, 0)
+	}
+	if n != 0 {
+		s.incrBy(n)
+	}
+}
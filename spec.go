@@ -0,0 +1,151 @@
+package mpb
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/vbauerster/mpb/decor"
+)
+
+// BarSpec is a declarative bar description, which can be unmarshalled from
+// config files, so end users can configure progress appearance.
+//
+// Decorators are referred by name, available ones are: "name", "percentage",
+// "counters", "counters-kib", "counters-kb", "elapsed", "eta", "speed-kib",
+// "speed-kb", "attempts".
+//
+// Available flags are: "trim", "trim-left", "trim-right",
+// "remove-on-complete", "clear-on-complete".
+type BarSpec struct {
+	// Name is displayed in front of prepend decorators, if not empty.
+	Name  string `json:"name,omitempty" yaml:"name,omitempty"`
+	Total int64  `json:"total" yaml:"total"`
+	// Style is bar format, like "[=>-]", container's one is used if empty.
+	Style   string   `json:"style,omitempty" yaml:"style,omitempty"`
+	Prepend []string `json:"prepend,omitempty" yaml:"prepend,omitempty"`
+	Append  []string `json:"append,omitempty" yaml:"append,omitempty"`
+	Flags   []string `json:"flags,omitempty" yaml:"flags,omitempty"`
+}
+
+var specDecorators = map[string]func() decor.Decorator{
+	"percentage": func() decor.Decorator { return decor.Percentage(decor.WCSyncSpace) },
+	"counters": func() decor.Decorator {
+		return decor.CountersNoUnit("%d / %d", decor.WCSyncSpace)
+	},
+	"counters-kib": func() decor.Decorator {
+		return decor.CountersKibiByte("% .1f / % .1f", decor.WCSyncSpace)
+	},
+	"counters-kb": func() decor.Decorator {
+		return decor.CountersKiloByte("% .1f / % .1f", decor.WCSyncSpace)
+	},
+	"elapsed": func() decor.Decorator { return decor.Elapsed(decor.ET_STYLE_GO, decor.WCSyncSpace) },
+	"eta":     func() decor.Decorator { return decor.ETA(decor.ET_STYLE_GO, decor.WCSyncSpace) },
+	"speed-kib": func() decor.Decorator {
+		return decor.AverageSpeed(decor.UnitKiB, "% .1f", decor.WCSyncSpace)
+	},
+	"speed-kb": func() decor.Decorator {
+		return decor.AverageSpeed(decor.UnitKB, "% .1f", decor.WCSyncSpace)
+	},
+	"attempts": func() decor.Decorator { return decor.Attempts("try %d", decor.WCSyncSpace) },
+}
+
+var specFlags = map[string]func() BarOption{
+	"trim":               BarTrim,
+	"trim-left":          BarTrimLeft,
+	"trim-right":         BarTrimRight,
+	"remove-on-complete": BarRemoveOnComplete,
+	"clear-on-complete":  BarClearOnComplete,
+}
+
+// AddFromSpec creates a new progress bar according to spec and adds to the
+// container. Provided options are applied after spec's ones. Returns error,
+// if spec refers to unknown decorator or flag, or style is malformed.
+func (p *Progress) AddFromSpec(spec BarSpec, options ...BarOption) (*Bar, error) {
+	specOptions, err := spec.options()
+	if err != nil {
+		return nil, err
+	}
+	return p.AddBar(spec.Total, append(specOptions, options...)...), nil
+}
+
+// AddBars creates a new progress bar for each spec and adds all of them to
+// the container at once, so they are rendered together from the very first
+// frame and width sync columns are negotiated once, rather than per added
+// bar. Returns error without adding any bar, if any spec is invalid.
+func (p *Progress) AddBars(specs []BarSpec) ([]*Bar, error) {
+	specOptions := make([][]BarOption, len(specs))
+	for i, spec := range specs {
+		options, err := spec.options()
+		if err != nil {
+			return nil, err
+		}
+		specOptions[i] = options
+	}
+	p.wg.Add(len(specs))
+	result := make(chan []*Bar)
+	select {
+	case p.operateState <- func(s *pState) {
+		bars := make([]*Bar, len(specs))
+		for i, spec := range specs {
+			bars[i] = s.addBar(p, spec.Total, specOptions[i])
+		}
+		result <- bars
+	}:
+		return <-result, nil
+	case <-p.done:
+		p.wg.Add(-len(specs))
+		return nil, nil
+	}
+}
+
+func (spec BarSpec) options() ([]BarOption, error) {
+	var options []BarOption
+	if spec.Style != "" {
+		if utf8.RuneCountInString(spec.Style) != formatLen {
+			return nil, fmt.Errorf("mpb: bar spec style %q must be %d runes long", spec.Style, formatLen)
+		}
+		options = append(options, barStyle(StyleFromFormat(spec.Style)))
+	}
+
+	var prepend []decor.Decorator
+	if spec.Name != "" {
+		prepend = append(prepend, decor.Name(spec.Name, decor.WCSyncSpaceR))
+	}
+	ds, err := specDecors(spec.Prepend)
+	if err != nil {
+		return nil, err
+	}
+	prepend = append(prepend, ds...)
+	if len(prepend) > 0 {
+		options = append(options, PrependDecorators(prepend...))
+	}
+
+	ds, err = specDecors(spec.Append)
+	if err != nil {
+		return nil, err
+	}
+	if len(ds) > 0 {
+		options = append(options, AppendDecorators(ds...))
+	}
+
+	for _, flag := range spec.Flags {
+		opt, ok := specFlags[flag]
+		if !ok {
+			return nil, fmt.Errorf("mpb: bar spec unknown flag %q", flag)
+		}
+		options = append(options, opt())
+	}
+	return options, nil
+}
+
+func specDecors(names []string) ([]decor.Decorator, error) {
+	ds := make([]decor.Decorator, 0, len(names))
+	for _, name := range names {
+		fn, ok := specDecorators[name]
+		if !ok {
+			return nil, fmt.Errorf("mpb: bar spec unknown decorator %q", name)
+		}
+		ds = append(ds, fn())
+	}
+	return ds, nil
+}
@@ -0,0 +1,88 @@
+package mpb_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	. "github.com/vbauerster/mpb"
+)
+
+func TestAddFromSpec(t *testing.T) {
+	config := `{
+		"name": "download",
+		"total": 100,
+		"style": "(#>_)",
+		"append": ["percentage"],
+		"flags": ["trim"]
+	}`
+
+	var spec BarSpec
+	if err := json.Unmarshal([]byte(config), &spec); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithWidth(30), WithOutputMode(ModeAppend))
+	bar, err := p.AddFromSpec(spec)
+	if err != nil {
+		t.Fatalf("AddFromSpec: %v", err)
+	}
+	bar.IncrBy(100)
+	p.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	last := lines[len(lines)-1]
+	if !strings.HasPrefix(last, "download (#") || !strings.HasSuffix(last, "#) 100 %") {
+		t.Errorf("unexpected last frame %q", last)
+	}
+}
+
+func TestAddFromSpecErrors(t *testing.T) {
+	specs := map[string]BarSpec{
+		"unknown decorator": {Total: 10, Append: []string{"nope"}},
+		"unknown flag":      {Total: 10, Flags: []string{"nope"}},
+		"bad style":         {Total: 10, Style: "[=]"},
+	}
+
+	p := New(WithOutput(ioutil.Discard))
+	for name, spec := range specs {
+		if bar, err := p.AddFromSpec(spec); err == nil || bar != nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	p.Wait()
+}
+
+func TestAddBars(t *testing.T) {
+	specs := []BarSpec{
+		{Name: "a", Total: 100, Append: []string{"percentage"}, Flags: []string{"trim"}},
+		{Name: "bbb", Total: 100, Append: []string{"percentage"}, Flags: []string{"trim"}},
+	}
+
+	var buf bytes.Buffer
+	p := New(WithOutput(&buf), WithWidth(30), WithOutputMode(ModeAppend))
+	bars, err := p.AddBars(specs)
+	if err != nil {
+		t.Fatalf("AddBars: %v", err)
+	}
+	if len(bars) != len(specs) {
+		t.Fatalf("want %d bars, got %d", len(specs), len(bars))
+	}
+	for _, bar := range bars {
+		bar.IncrBy(100)
+	}
+	p.Wait()
+
+	// both bars are present in the first frame, with synced name column
+	lines := strings.Split(buf.String(), "\n")
+	if len(lines) < 2 || !strings.HasPrefix(lines[0], "a   ") || !strings.HasPrefix(lines[1], "bbb ") {
+		t.Errorf("unexpected first frame %q", buf.String())
+	}
+
+	if _, err := p.AddBars([]BarSpec{{Total: 10, Flags: []string{"nope"}}}); err == nil {
+		t.Error("expected error")
+	}
+}
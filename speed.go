@@ -0,0 +1,67 @@
+package mpb
+
+import "time"
+
+// default window of decor.Statistics.CurrentSpeed, see BarSpeedWindow
+const defaultSpeedWindow = 5 * time.Second
+
+// speedSample is amount done, excluding refilled one, by the bar's
+// elapsed time at.
+type speedSample struct {
+	at time.Duration
+	n  int64
+}
+
+// trackSpeed samples amount done once per frame, while the bar is running,
+// keeping samples within speed window only, and one before it as the base.
+func (s *bState) trackSpeed() {
+	if s.toComplete || s.spinner || !s.pausedAt.IsZero() {
+		return
+	}
+	now := s.elapsed()
+	window := s.speedWindow
+	if window <= 0 {
+		window = defaultSpeedWindow
+	}
+	var i int
+	for i+1 < len(s.speedSamples) && s.speedSamples[i+1].at <= now-window {
+		i++
+	}
+	if i > 0 {
+		s.speedSamples = append(s.speedSamples[:0], s.speedSamples[i:]...)
+	}
+	s.speedSamples = append(s.speedSamples, speedSample{now, s.current - s.refilled()})
+}
+
+// speedSampled reports whether there are enough samples to tell current
+// speed, i.e. base one and at least one more.
+func (s *bState) speedSampled() bool {
+	return len(s.speedSamples) > 1
+}
+
+// currentSpeed is rate per second since the oldest sample, zero if there
+// aren't enough samples yet.
+func (s *bState) currentSpeed() float64 {
+	if !s.speedSampled() {
+		return 0
+	}
+	base := s.speedSamples[0]
+	d := s.elapsed() - base.at
+	if d <= 0 {
+		return 0
+	}
+	speed := float64(s.current-s.refilled()-base.n) / d.Seconds()
+	if speed < 0 {
+		return 0
+	}
+	return speed
+}
+
+// averageSpeed is rate per second since the bar has started.
+func (s *bState) averageSpeed() float64 {
+	d := s.elapsed()
+	if d <= 0 {
+		return 0
+	}
+	return float64(s.current-s.refilled()) / d.Seconds()
+}
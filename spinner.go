@@ -0,0 +1,65 @@
+package mpb
+
+import "github.com/vbauerster/mpb/internal"
+
+// SpinnerPosition defines where spinner is rendered,
+// relative to the bar's decorators.
+type SpinnerPosition int
+
+const (
+	// SpinnerOnMiddle renders spinner in place of the bar section. This is default.
+	SpinnerOnMiddle SpinnerPosition = iota
+	// SpinnerOnLeft renders spinner before prepend decorators.
+	SpinnerOnLeft
+	// SpinnerOnRight renders spinner after append decorators.
+	SpinnerOnRight
+)
+
+// Predefined spinner styles, see BarSpinnerStyle.
+var (
+	SpinnerASCII   = []string{"-", "\\", "|", "/"}
+	SpinnerBraille = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	SpinnerCircle  = []string{"◐", "◓", "◑", "◒"}
+	SpinnerBounce  = []string{"[=   ]", "[ =  ]", "[  = ]", "[   =]", "[  = ]", "[ =  ]"}
+)
+
+var defaultSpinnerFrames = SpinnerASCII
+
+// fillSpinner writes next spinner frame to bufB,
+// honoring trim options the same way fillBar does.
+// Frames narrower than the widest one are padded, so the line doesn't jitter.
+func (s *bState) fillSpinner() {
+	frames := s.spinnerFrames
+	if len(frames) == 0 {
+		frames = defaultSpinnerFrames
+	}
+	s.bufB.Reset()
+	if !s.trimLeftSpace {
+		s.bufB.WriteByte(' ')
+	}
+	frame := frames[s.spinnerCount%len(frames)]
+	s.bufB.WriteString(frame)
+	for i := internal.DisplayWidth(frame); i < s.spinnerWidth; i++ {
+		s.bufB.WriteByte(' ')
+	}
+	if !s.trimRightSpace {
+		s.bufB.WriteByte(' ')
+	}
+	if s.spinnerInterval <= 0 {
+		s.spinnerCount++
+	} else if now := s.now(); now.Sub(s.spinnerLast) >= s.spinnerInterval {
+		if !s.spinnerLast.IsZero() {
+			s.spinnerCount++
+		}
+		s.spinnerLast = now
+	}
+}
+
+func (s *bState) drawSpinner() {
+	s.fillSpinner()
+	if s.spinnerPos == SpinnerOnRight {
+		// bufA is the last section, so the spinner goes there
+		s.bufA.Write(s.bufB.Bytes())
+		s.bufB.Reset()
+	}
+}
@@ -0,0 +1,94 @@
+package mpb
+
+import (
+	"math"
+	"unicode/utf8"
+
+	"github.com/vbauerster/mpb/internal"
+)
+
+// formatLen is number of runes of a format string, like "[=>-]",
+// which are left edge, fill, tip, empty and right edge respectively.
+const formatLen = 5
+
+// BarStyle defines how the bar section is drawn. Fill and Empty are drawn
+// once per cell, so they should be one cell wide. Tip is drawn at the
+// leading edge of filled part and may be several cells wide, e.g. "=>".
+// Smooth, if any, are partially filled cells in ascending order, like
+// "▏▎▍▌▋▊▉". The leading cell is drawn with one of them, according to
+// the done fraction of the cell, instead of Tip, so the bar advances by
+// 1/(len(Smooth)+1) of a cell, rather than by whole cells.
+type BarStyle struct {
+	Left, Fill, Tip, Empty, Right string
+	Smooth                        []string
+}
+
+// Predefined bar styles, see WithBarStyle and BarWithStyle.
+var (
+	StyleDefault = BarStyle{Left: "[", Fill: "=", Tip: ">", Empty: "-", Right: "]"}
+	StyleBlocks  = BarStyle{
+		Left:   "│",
+		Fill:   "█",
+		Empty:  " ",
+		Right:  "│",
+		Smooth: []string{"▏", "▎", "▍", "▌", "▋", "▊", "▉"},
+	}
+)
+
+// StyleFromFormat returns BarStyle of format string, like "[=>-]",
+// see WithFormat. Format, which isn't 5 runes long, results in StyleDefault.
+func StyleFromFormat(format string) BarStyle {
+	if utf8.RuneCountInString(format) != formatLen {
+		return StyleDefault
+	}
+	var parts [formatLen]string
+	for i := range parts {
+		_, n := utf8.DecodeRuneInString(format)
+		parts[i], format = format[:n], format[n:]
+	}
+	return BarStyle{
+		Left:  parts[0],
+		Fill:  parts[1],
+		Tip:   parts[2],
+		Empty: parts[3],
+		Right: parts[4],
+	}
+}
+
+// progress returns number of filled cells out of barWidth and partially
+// filled cell, if style is smooth and the leading cell isn't empty.
+func (style BarStyle) progress(total, current, barWidth int64) (int64, string) {
+	if len(style.Smooth) == 0 || total <= 0 {
+		return internal.Percentage(total, current, barWidth), ""
+	}
+	cells := float64(barWidth*current) / float64(total)
+	full := math.Floor(cells)
+	if int64(full) >= barWidth {
+		return barWidth, ""
+	}
+	level := int((cells - full) * float64(len(style.Smooth)+1))
+	if level == 0 {
+		return int64(full), ""
+	}
+	return int64(full), style.Smooth[level-1]
+}
+
+// mirrors are runes, which mirror opposite one, see mirror.
+var mirrors = map[rune]rune{'<': '>', '>': '<', '(': ')', ')': '(', '[': ']', ']': '[', '{': '}', '}': '{', '/': '\\', '\\': '/'}
+
+// mirror returns str in reverse order of runes, with mirrored ones swapped,
+// so a tip, like "=>", leads leftwards, see BarReverse.
+func mirror(str string) string {
+	runes := []rune(str)
+	for i, j := 0, len(runes)-1; i <= j; i, j = i+1, j-1 {
+		ri, rj := runes[i], runes[j]
+		if m, ok := mirrors[ri]; ok {
+			ri = m
+		}
+		if m, ok := mirrors[rj]; ok {
+			rj = m
+		}
+		runes[i], runes[j] = rj, ri
+	}
+	return string(runes)
+}
@@ -0,0 +1,39 @@
+package mpb
+
+import (
+	"bytes"
+	"io"
+	"time"
+)
+
+// timestampWriter prefixes each line with a timestamp.
+// All lines of a single Write call share the same timestamp,
+// so in effect each frame gets its own one.
+type timestampWriter struct {
+	out    io.Writer
+	layout string
+	clock  Clock
+	buf    bytes.Buffer
+}
+
+func (w *timestampWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	now := time.Now()
+	if w.clock != nil {
+		now = w.clock.Now()
+	}
+	prefix := now.Format(w.layout) + " "
+	w.buf.Reset()
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		w.buf.WriteString(prefix)
+		if i < 0 {
+			w.buf.Write(p)
+			break
+		}
+		w.buf.Write(p[:i+1])
+		p = p[i+1:]
+	}
+	_, err := w.buf.WriteTo(w.out)
+	return n, err
+}
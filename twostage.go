@@ -0,0 +1,85 @@
+package mpb
+
+import (
+	"fmt"
+
+	"github.com/vbauerster/mpb/decor"
+)
+
+// TwoStageBar is a bar for "scanning... then processing..." kind of jobs.
+// At stage one it is rendered as spinner, counting discovered items, at
+// stage two it is an ordinary bar, which total is number of items found.
+type TwoStageBar struct {
+	*Bar
+	label *stageLabel
+}
+
+// AddTwoStageBar creates a new two stage bar and adds to the container.
+// Stage one is labeled with scanLabel followed by discovered items count,
+// stage two is labeled with workLabel. Call Found, while discovering items,
+// then StartWork to switch to stage two. Stages are recorded as phases,
+// named by their labels, see SetPhase.
+func (p *Progress) AddTwoStageBar(scanLabel, workLabel string, options ...BarOption) *TwoStageBar {
+	label := &stageLabel{scanLabel: scanLabel, workLabel: workLabel}
+	label.Init()
+	options = append([]BarOption{PrependDecorators(label), barPhase(scanLabel)}, options...)
+	bar := p.AddBar(0, options...)
+	if bar == nil {
+		return nil
+	}
+	return &TwoStageBar{Bar: bar, label: label}
+}
+
+// Found increments discovered items count by n. Effective at stage one only.
+func (b *TwoStageBar) Found(n int) {
+	select {
+	case b.operateState <- func(s *bState) {
+		if !b.label.working {
+			s.incrBy(int64(n))
+		}
+	}:
+	case <-b.done:
+	}
+}
+
+// StartWork switches the bar to stage two, with total set to number of
+// discovered items and progress reset to zero. If nothing has been found,
+// the bar completes right away.
+func (b *TwoStageBar) StartWork() {
+	select {
+	case b.operateState <- func(s *bState) {
+		if b.label.working {
+			return
+		}
+		b.label.working = true
+		s.spinner = false
+		s.restartClock(s.now())
+		s.switchPhase(b.label.workLabel, s.startTime)
+		// placeholder total of scan stage is dropped, even if nothing
+		// has been found, so counters don't show it
+		s.total = s.current
+		if s.current == 0 {
+			s.toComplete = true
+			return
+		}
+		s.current = 0
+	}:
+	case <-b.done:
+	}
+}
+
+// stageLabel is mutated by the bar's goroutine only, same one which calls
+// Decor, so no synchronization is needed.
+type stageLabel struct {
+	decor.WC
+	scanLabel string
+	workLabel string
+	working   bool
+}
+
+func (d *stageLabel) Decor(st *decor.Statistics) string {
+	if d.working {
+		return d.FormatMsg(d.workLabel)
+	}
+	return d.FormatMsg(fmt.Sprintf("%s %d", d.scanLabel, st.Current))
+}
@@ -0,0 +1,16 @@
+package mpb
+
+import "github.com/vbauerster/mpb/internal"
+
+// DisplayWidth returns number of terminal cells, s would occupy, by the same
+// rules the renderer applies: ANSI escape sequences are not counted and East
+// Asian wide runes are counted as two cells.
+func DisplayWidth(s string) int {
+	return internal.DisplayWidth(s)
+}
+
+// TruncateToWidth cuts s, so its display width doesn't exceed width.
+// ANSI escape sequences are preserved.
+func TruncateToWidth(s string, width int) string {
+	return internal.Truncate(s, width, "")
+}